@@ -0,0 +1,272 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package async0 implements asynchronous buffered writer.
+package async0
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Overflow policies.
+const (
+	Block      = iota // Block blocks the Write until the queue has a room.
+	DropNewest        // DropNewest drops the written entry.
+	DropOldest        // DropOldest drops the oldest entry of the queue.
+)
+
+var (
+	// ErrClosed is returned by the Write after the Close.
+	ErrClosed = errors.New("async0: writer is closed")
+	// ErrTimeout is returned by the Flush and Close if the queue is not written in time.
+	ErrTimeout = errors.New("async0: timeout")
+)
+
+// Writer copies entries into the bounded queue
+// which is written to the Output by the background goroutine.
+type Writer struct {
+	dropped uint64 // dropped is a number of the dropped entries, first field for the 64-bit alignment of the atomic operations.
+
+	Output   io.Writer // Output is a destination for output.
+	Size     int       // Size is a maximum number of the entries in the queue, 1024 if zero.
+	Overflow uint8     // Overflow is a policy of the overflow of the queue: all except known = block; 1 = drop newest; 2 = drop oldest.
+
+	once      sync.Once
+	closeOnce sync.Once
+	queue     chan *[]byte
+	closing   chan struct{} // closing is closed by the Close, blocked Writes give up.
+	idle      chan struct{} // idle is closed when no Write is in progress after the Close.
+	done      chan struct{}
+
+	mu      sync.Mutex // mu guards pending entries, waiters, senders and error.
+	pending int
+	waiters []chan struct{}
+	senders int // senders is a number of the Writes in progress.
+	closed  bool
+	err     error // err is a first write error since the last Flush.
+}
+
+var bufPool = sync.Pool{New: func() interface{} { return new([]byte) }}
+
+func (w *Writer) start() {
+	size := w.Size
+	if size <= 0 {
+		size = 1024
+	}
+
+	w.queue = make(chan *[]byte, size)
+	w.closing = make(chan struct{})
+	w.idle = make(chan struct{})
+	w.done = make(chan struct{})
+
+	go w.run()
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case p := <-w.queue:
+			w.write(p)
+
+		case <-w.idle:
+			for {
+				select {
+				case p := <-w.queue:
+					w.write(p)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *Writer) write(p *[]byte) {
+	_, err := w.Output.Write(*p)
+	bufPool.Put(p)
+
+	w.mu.Lock()
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+
+	w.add(-1)
+}
+
+// add adds delta to the number of the pending entries
+// and notifies waiters if no entries are pending.
+func (w *Writer) add(delta int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending += delta
+	if w.pending != 0 {
+		return
+	}
+
+	for _, c := range w.waiters {
+		close(c)
+	}
+	w.waiters = w.waiters[:0]
+}
+
+func (w *Writer) drop(p *[]byte) {
+	bufPool.Put(p)
+	atomic.AddUint64(&w.dropped, 1)
+}
+
+// Write copies the entry into the queue.
+// Dropped entries are not reported as an error.
+func (w *Writer) Write(src []byte) (int, error) {
+	w.once.Do(w.start)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+	w.senders++
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		w.senders--
+		if w.closed && w.senders == 0 {
+			close(w.idle)
+		}
+		w.mu.Unlock()
+	}()
+
+	p := bufPool.Get().(*[]byte)
+	*p = append((*p)[:0], src...)
+
+	w.add(1)
+
+	switch w.Overflow {
+	case DropNewest:
+		select {
+		case w.queue <- p:
+		default:
+			w.add(-1)
+			w.drop(p)
+		}
+
+	case DropOldest:
+		for {
+			select {
+			case w.queue <- p:
+				return len(src), nil
+			default:
+			}
+
+			select {
+			case old := <-w.queue:
+				w.add(-1)
+				w.drop(old)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case w.queue <- p:
+		case <-w.closing:
+			w.add(-1)
+			bufPool.Put(p)
+			return 0, ErrClosed
+		}
+	}
+
+	return len(src), nil
+}
+
+// Flush waits until all queued entries are written
+// and returns the first write error since the last Flush.
+// No timeout if timeout is zero.
+func (w *Writer) Flush(timeout time.Duration) error {
+	w.mu.Lock()
+
+	if w.pending == 0 {
+		err := w.err
+		w.err = nil
+		w.mu.Unlock()
+		return err
+	}
+
+	c := make(chan struct{})
+	w.waiters = append(w.waiters, c)
+
+	w.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	select {
+	case <-c:
+	case <-expired:
+		return ErrTimeout
+	}
+
+	w.mu.Lock()
+	err := w.err
+	w.err = nil
+	w.mu.Unlock()
+
+	return err
+}
+
+// Close stops accepting entries, waits until all queued entries
+// are written and returns the first write error since the last Flush.
+// Writes blocked by the full queue return ErrClosed.
+// No timeout if timeout is zero.
+func (w *Writer) Close(timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	w.once.Do(w.start)
+
+	w.closeOnce.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		if w.senders == 0 {
+			close(w.idle)
+		}
+		w.mu.Unlock()
+
+		close(w.closing)
+	})
+
+	select {
+	case <-w.done:
+	case <-expired:
+		return ErrTimeout
+	}
+
+	w.mu.Lock()
+	err := w.err
+	w.err = nil
+	w.mu.Unlock()
+
+	return err
+}
+
+// Dropped returns number of the dropped entries.
+func (w *Writer) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
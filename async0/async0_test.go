@@ -0,0 +1,261 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package async0_test
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/async0"
+)
+
+// gate is a writer which blocks writes until release.
+type gate struct {
+	started chan struct{}
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func newGate() *gate {
+	return &gate{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (g *gate) Write(p []byte) (int, error) {
+	g.started <- struct{}{}
+	<-g.release
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.Write(p)
+}
+
+func (g *gate) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.String()
+}
+
+func TestWriterLog(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	w := &async0.Writer{Output: &out}
+	errW := &async0.Writer{Output: &errOut}
+
+	l := &log0.Log{
+		Output: w,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Severity: func(severity string) io.Writer {
+			if severity <= "3" {
+				return errW
+			}
+			return nil
+		},
+	}
+
+	for i := 0; i < 100; i++ {
+		err := l.Info(fmt.Sprintf("foo %d", i))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	err := l.Error("bar")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Close(time.Second)
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	err = errW.Close(time.Second)
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 100 || lines[99] != `{"message":"foo 99","severity":"6"}` {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	if errOut.String() != `{"message":"bar","severity":"3"}`+"\n" {
+		t.Errorf("unexpected error output: %s", errOut.String())
+	}
+}
+
+var OverflowTestCases = []struct {
+	name     string
+	overflow uint8
+	expected string
+}{
+	{name: "drop newest", overflow: async0.DropNewest, expected: "1\n2\n3\n"},
+	{name: "drop oldest", overflow: async0.DropOldest, expected: "1\n4\n5\n"},
+}
+
+func TestWriterOverflow(t *testing.T) {
+	for _, tc := range OverflowTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := newGate()
+
+			w := &async0.Writer{Output: g, Size: 2, Overflow: tc.overflow}
+
+			_, err := w.Write([]byte("1\n"))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			<-g.started
+
+			for i := 2; i <= 5; i++ {
+				_, err = w.Write([]byte(fmt.Sprintf("%d\n", i)))
+				if err != nil {
+					t.Fatalf("unexpected write error: %s", err)
+				}
+			}
+
+			if w.Dropped() != 2 {
+				t.Errorf("unexpected number of the dropped entries: %d", w.Dropped())
+			}
+
+			close(g.release)
+
+			err = w.Flush(time.Second)
+			if err != nil {
+				t.Fatalf("unexpected flush error: %s", err)
+			}
+
+			if g.String() != tc.expected {
+				t.Errorf("unexpected output, expected: %q, received: %q", tc.expected, g.String())
+			}
+		})
+	}
+}
+
+func TestWriterFlushTimeout(t *testing.T) {
+	g := newGate()
+
+	w := &async0.Writer{Output: g}
+
+	_, err := w.Write([]byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Flush(10 * time.Millisecond)
+	if !errors.Is(err, async0.ErrTimeout) {
+		t.Errorf("unexpected flush error: %v", err)
+	}
+
+	close(g.release)
+
+	err = w.Close(time.Second)
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	_, err = w.Write([]byte("bar"))
+	if !errors.Is(err, async0.ErrClosed) {
+		t.Errorf("unexpected write error after close: %v", err)
+	}
+
+	if g.String() != "foo" {
+		t.Errorf("unexpected output: %q", g.String())
+	}
+}
+
+func TestWriterCloseTimeout(t *testing.T) {
+	g := newGate()
+	defer close(g.release)
+
+	w := &async0.Writer{Output: g, Size: 1}
+
+	_, err := w.Write([]byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	<-g.started
+
+	_, err = w.Write([]byte("bar"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	blocked := make(chan error)
+	go func() {
+		_, err := w.Write([]byte("xyz"))
+		blocked <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond) // Lets the write block on the full queue.
+
+	closed := make(chan error)
+	go func() { closed <- w.Close(50 * time.Millisecond) }()
+
+	select {
+	case err = <-closed:
+		if !errors.Is(err, async0.ErrTimeout) {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("close is blocked by the hung output")
+	}
+
+	select {
+	case err = <-blocked:
+		if !errors.Is(err, async0.ErrClosed) {
+			t.Errorf("unexpected blocked write error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("write is blocked after close")
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) { return 0, errors.New("foo") }
+
+func TestWriterError(t *testing.T) {
+	w := &async0.Writer{Output: errWriter{}}
+
+	_, err := w.Write([]byte("bar"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Flush(time.Second)
+	if err == nil || err.Error() != "foo" {
+		t.Errorf("unexpected flush error: %v", err)
+	}
+
+	err = w.Flush(time.Second)
+	if err != nil {
+		t.Errorf("unexpected second flush error: %v", err)
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	w := &async0.Writer{Output: io.Discard, Overflow: async0.DropOldest}
+	defer w.Close(time.Second)
+	p := []byte(`{"message":"Hello, World!"}` + "\n")
+	for i := 0; i < b.N; i++ {
+		_, err := w.Write(p)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0
+
+import "context"
+
+type loggerKey struct{}
+
+type kvKey struct{}
+
+// NewContext returns a copy of the parent context with the logger.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger of the context.
+func FromContext(ctx context.Context) (Logger, bool) {
+	l, ok := ctx.Value(loggerKey{}).(Logger)
+	return l, ok
+}
+
+// WithKV returns a copy of the parent context with an additional key-values.
+// Key-values of the parent context have a lower priority
+// than the priority of the newer key-values.
+func WithKV(ctx context.Context, kv ...KV) context.Context {
+	parent := KVFromContext(ctx)
+	kvs := make([]KV, 0, len(parent)+len(kv))
+	kvs = append(append(kvs, parent...), kv...)
+	return context.WithValue(ctx, kvKey{}, kvs)
+}
+
+// KVFromContext returns key-values of the context.
+func KVFromContext(ctx context.Context) []KV {
+	kv, _ := ctx.Value(kvKey{}).([]KV)
+	return kv
+}
+
+// Extractor returns key-values extracted from the context.
+type Extractor func(ctx context.Context) []KV
+
+// ValueExtractor returns extractor of the value of the context key,
+// for example request ID or user ID.
+// Nothing is extracted if the context has no value of the key.
+func ValueExtractor(k string, key interface{}) Extractor {
+	return func(ctx context.Context) []KV {
+		v := ctx.Value(key)
+		if v == nil {
+			return nil
+		}
+		return []KV{StringAny(k, v)}
+	}
+}
+
+// DeadlineExtractor returns extractor of the deadline of the context.
+// Nothing is extracted if the context has no deadline.
+func DeadlineExtractor(k string) Extractor {
+	return func(ctx context.Context) []KV {
+		d, ok := ctx.Deadline()
+		if !ok {
+			return nil
+		}
+		return []KV{StringTime(k, d)}
+	}
+}
+
+// Ctx returns copy of the logger with an additional key-values
+// obtained from the extractors, from the context and from the arguments
+// in order of increasing priority.
+// If first argument key-value implements the KVS interface
+// then it is the first key-value of the copy (see Get).
+func (l *Log) Ctx(ctx context.Context, kv ...KV) Logger {
+	var kvs []KV
+
+	if len(kv) > 0 {
+		if s, ok := kv[0].(KVS); ok {
+			kvs = append(kvs, s)
+			kv = kv[1:]
+		}
+	}
+
+	for _, e := range l.Extractors {
+		kvs = append(kvs, e(ctx)...)
+	}
+
+	kvs = append(append(kvs, KVFromContext(ctx)...), kv...)
+
+	return l.Get(kvs...)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0_test
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/kinbiko/jsonassert"
+)
+
+type requestIDKey struct{}
+
+func TestContextLogger(t *testing.T) {
+	ctx := context.Background()
+
+	_, ok := log0.FromContext(ctx)
+	if ok {
+		t.Fatal("unexpected logger of the empty context")
+	}
+
+	l := &log0.Log{}
+
+	l1, ok := log0.FromContext(log0.NewContext(ctx, l))
+	if !ok || l1 != l {
+		t.Errorf("unexpected logger of the context: %v", l1)
+	}
+}
+
+func TestCtx(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	deadline := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	ctx = context.WithValue(ctx, requestIDKey{}, "42")
+	ctx = log0.WithKV(ctx, log0.Strings("foo", "bar"), log0.StringInt("xyz", 1))
+	ctx = log0.WithKV(ctx, log0.Strings("foo", "baz"))
+
+	l := &log0.Log{
+		Output: &out,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Severity: func(severity string) io.Writer {
+			if severity <= "3" {
+				return &errOut
+			}
+			return nil
+		},
+		Extractors: []log0.Extractor{
+			log0.ValueExtractor("request_id", requestIDKey{}),
+			log0.ValueExtractor("user_id", struct{}{}),
+			log0.DeadlineExtractor("deadline"),
+		},
+	}
+
+	ctx = log0.NewContext(ctx, l)
+
+	l0, ok := log0.FromContext(ctx)
+	if !ok {
+		t.Fatal("unexpected context without logger")
+	}
+
+	l1 := l0.Ctx(ctx, log0.StringSeverity("severity", "3"), log0.StringInt("xyz", 2))
+	defer l1.Put()
+
+	_, err := l1.Write([]byte("Hello, World!"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	if out.Len() != 0 {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(errOut.String(), `{
+		"message":"Hello, World!",
+		"severity":"3",
+		"request_id":"42",
+		"deadline":"2020-10-15T18:09:00Z",
+		"foo":"baz",
+		"xyz":2
+	}`)
+}
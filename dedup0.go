@@ -0,0 +1,231 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0
+
+import (
+	"encoding"
+	"sync"
+	"time"
+)
+
+// Dedup suppresses identical entries within the window.
+// First entry is written immediately and the identical entries
+// (the same message and key-values except timestamp) are counted
+// until the window which starts at the first entry expires.
+// On the window expiry the first entry is written again
+// with the number of the suppressed entries and the first and last seen timestamps
+// which are written outside of the namespace and override the key-values of the same keys.
+// Output of the logger should be safe for concurrent use
+// because expired entries are written by the timers.
+// Dedup is safe for concurrent use by the loggers obtained by the Get.
+type Dedup struct {
+	Window time.Duration             // Window is a duration of the suppression, one second if zero.
+	Keys   [3]encoding.TextMarshaler // Keys: 0 = number of the suppressed entries; 1 = first seen timestamp; 2 = last seen timestamp; "repeated", "first_seen" and "last_seen" if nil.
+	Clock  Clock                     // Clock is a source of the timestamps, system clock is used if nil.
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	swept   time.Time // swept is a time of the last removal of the expired entries.
+}
+
+var dedupKeys = [3]encoding.TextMarshaler{
+	String("repeated"),
+	String("first_seen"),
+	String("last_seen"),
+}
+
+// dedupEntry is a state of the identical entries.
+type dedupEntry struct {
+	log     *Log        // log is a copy of the logger of the first entry.
+	src     []byte      // src is a message of the first entry.
+	pc      uintptr     // pc is a program counter of the caller of the first entry.
+	pcs     []uintptr   // pcs is a stack trace of the first entry.
+	dropped uint64      // dropped is a number of the dropped entries before the first entry.
+	first   time.Time   // first is a time of the first entry.
+	last    time.Time   // last is a time of the last suppressed entry.
+	n       uint64      // n is a number of the suppressed entries.
+	timer   *time.Timer // timer writes entry on the window expiry.
+	done    bool        // done reports whether the entry is expired or flushed.
+}
+
+func (d *Dedup) now() time.Time {
+	if d.Clock == nil {
+		return time.Now()
+	}
+	return d.Clock.Now()
+}
+
+func (d *Dedup) window() time.Duration {
+	if d.Window == 0 {
+		return time.Second
+	}
+	return d.Window
+}
+
+// seen reports whether the entry is written
+// or suppressed as identical to the previous entry.
+// Entry p is encoded without timestamp from the src by the encoder.
+func (d *Dedup) seen(l *Log, enc *encoder, src []byte, p []byte) (bool, error) {
+	now := d.now()
+	window := d.window()
+
+	d.mu.Lock()
+
+	if d.entries == nil {
+		d.entries = make(map[string]*dedupEntry)
+	}
+
+	if now.Sub(d.swept) >= window {
+		for k, e := range d.entries {
+			if e.n == 0 && now.Sub(e.first) >= window {
+				e.done = true
+				delete(d.entries, k)
+			}
+		}
+		d.swept = now
+	}
+
+	e, ok := d.entries[string(p)]
+	if ok && now.Sub(e.first) < window {
+		e.n++
+		e.last = now
+		if e.timer == nil {
+			key := string(p)
+			e.timer = time.AfterFunc(window-now.Sub(e.first), func() { d.expire(key, e) })
+		}
+		d.mu.Unlock()
+		return false, nil
+	}
+
+	var expired *dedupEntry
+	if ok && !e.done {
+		e.done = true
+		if e.timer != nil {
+			e.timer.Stop()
+		}
+		if e.n != 0 {
+			expired = e
+		}
+	}
+
+	d.entries[string(p)] = &dedupEntry{
+		log:     l.detach(),
+		src:     append([]byte(nil), src...),
+		pc:      enc.pc[0],
+		pcs:     append([]uintptr(nil), enc.st.V.PC...),
+		dropped: enc.dr.N,
+		first:   now,
+		last:    now,
+	}
+
+	d.mu.Unlock()
+
+	if expired != nil {
+		return true, d.write(expired)
+	}
+
+	return true, nil
+}
+
+// expire writes the entry on the window expiry.
+func (d *Dedup) expire(key string, e *dedupEntry) {
+	d.mu.Lock()
+	if e.done {
+		d.mu.Unlock()
+		return
+	}
+	e.done = true
+	if d.entries[key] == e {
+		delete(d.entries, key)
+	}
+	d.mu.Unlock()
+
+	_ = d.write(e)
+}
+
+// Flush writes all entries with the suppressed identical entries
+// and resets the state, for example on shutdown.
+func (d *Dedup) Flush() error {
+	d.mu.Lock()
+
+	var expired []*dedupEntry
+	for k, e := range d.entries {
+		if !e.done {
+			e.done = true
+			if e.timer != nil {
+				e.timer.Stop()
+			}
+			if e.n != 0 {
+				expired = append(expired, e)
+			}
+		}
+		delete(d.entries, k)
+	}
+
+	d.mu.Unlock()
+
+	var err error
+	for _, e := range expired {
+		e0 := d.write(e)
+		if e0 != nil && err == nil {
+			err = e0
+		}
+	}
+
+	return err
+}
+
+// write writes the entry with the number of the suppressed entries
+// and the first and last seen timestamps, the timestamp of the entry
+// is the last seen timestamp.
+func (d *Dedup) write(e *dedupEntry) error {
+	if e.log.Output == nil {
+		return nil
+	}
+
+	keys := d.Keys
+	for i, k := range keys {
+		if k == nil {
+			keys[i] = dedupKeys[i]
+		}
+	}
+
+	first, last := e.first, e.last
+	if e.log.Location != nil {
+		first, last = first.In(e.log.Location), last.In(e.log.Location)
+	}
+
+	l := *e.log
+	l.Clock = dedupClock(e.last)
+	l.summary = []KV{
+		TextUint64(keys[0], e.n),
+		&timestamp{K: keys[1], V: first, F: l.TimeFormat},
+		&timestamp{K: keys[2], V: last, F: l.TimeFormat},
+	}
+
+	enc := encoderPool.Get().(*encoder)
+	defer encoderPool.Put(enc)
+
+	enc.dr.N = e.dropped
+	enc.pc[0] = e.pc
+	enc.st.V.PC = nil
+	if len(e.pcs) != 0 {
+		enc.st = stack{K: l.Stack, V: stackV{PC: e.pcs, F: l.StackFormat}}
+	}
+
+	err := l.json(enc, e.src)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.Output.Write(enc.p)
+
+	return err
+}
+
+// dedupClock is a clock of the last seen timestamp.
+type dedupClock time.Time
+
+func (c dedupClock) Now() time.Time { return time.Time(c) }
@@ -0,0 +1,193 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0_test
+
+import (
+	"bytes"
+	"encoding"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+)
+
+// syncBuffer is a bytes buffer which is safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDedupFlush(t *testing.T) {
+	var buf syncBuffer
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	l := &log0.Log{
+		Output:     &buf,
+		Keys:       [4]encoding.TextMarshaler{log0.String("message")},
+		Time:       log0.String("time"),
+		TimeFormat: log0.RFC3339,
+		Clock:      tickClock{t: &now},
+		Dedup: &log0.Dedup{
+			Window: time.Hour,
+			Clock:  tickClock{t: &now},
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		err := l.Error("foo", log0.StringInt("code", 42))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	err := l.Error("foo", log0.StringInt("code", 43))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = l.Dedup.Flush()
+	if err != nil {
+		t.Fatalf("unexpected flush error: %s", err)
+	}
+
+	expected := []string{
+		`{"code":42,"message":"foo","severity":"3","time":"2020-10-15T18:09:00Z"}`,
+		`{"code":43,"message":"foo","severity":"3","time":"2020-10-15T18:09:03Z"}`,
+		`{"code":42,"first_seen":"2020-10-15T18:09:00Z","last_seen":"2020-10-15T18:09:02Z","message":"foo","repeated":2,"severity":"3","time":"2020-10-15T18:09:02Z"}`,
+	}
+
+	if buf.String() != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("unexpected output, expected:\n%s\nreceived:\n%s", strings.Join(expected, "\n"), buf.String())
+	}
+}
+
+func TestDedupWindowExpiry(t *testing.T) {
+	var buf syncBuffer
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Dedup: &log0.Dedup{
+			Window: 20 * time.Millisecond,
+			Keys:   [3]encoding.TextMarshaler{log0.String("count")},
+		},
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := l.Write([]byte("foo"))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), `"count":4`) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 2 || lines[0] != `{"message":"foo"}` || !strings.HasPrefix(lines[1], `{"count":4,"first_seen":"`) || !strings.HasSuffix(lines[1], `,"message":"foo"}`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+
+	_, err := l.Write([]byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Errorf("unexpected number of the entries after the window expiry: %d, output: %s", n, buf.String())
+	}
+}
+
+func TestDedupGELF(t *testing.T) {
+	var buf syncBuffer
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	l := log0.GELF()
+	l.Output = &buf
+	l.KV = []log0.KV{log0.Strings("version", "1.1")}
+	l.Clock = tickClock{t: &now}
+	l.Dedup = &log0.Dedup{Window: time.Hour, Clock: tickClock{t: &now}}
+
+	for i := 0; i < 2; i++ {
+		err := l.Error("foo")
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	err := l.Dedup.Flush()
+	if err != nil {
+		t.Fatalf("unexpected flush error: %s", err)
+	}
+
+	expected := []string{
+		`{"level":3,"short_message":"foo","timestamp":1602785340,"version":"1.1"}`,
+		`{"_first_seen":1602785340,"_last_seen":1602785341,"_repeated":1,"level":3,"short_message":"foo","timestamp":1602785341,"version":"1.1"}`,
+	}
+
+	if buf.String() != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("unexpected output, expected:\n%s\nreceived:\n%s", strings.Join(expected, "\n"), buf.String())
+	}
+}
+
+func TestDedupKeyCollision(t *testing.T) {
+	var buf syncBuffer
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	l := &log0.Log{
+		Output:     &buf,
+		KV:         []log0.KV{log0.StringInt("repeated", 7), log0.StringNamespace("http"), log0.StringInt("status", 200)},
+		Keys:       [4]encoding.TextMarshaler{log0.String("message")},
+		Order:      log0.Insertion,
+		Time:       log0.String("time"),
+		TimeFormat: log0.RFC3339,
+		Clock:      tickClock{t: &now},
+		Dedup:      &log0.Dedup{Window: time.Hour, Clock: tickClock{t: &now}},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := l.Write([]byte("foo"))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	err := l.Dedup.Flush()
+	if err != nil {
+		t.Fatalf("unexpected flush error: %s", err)
+	}
+
+	expected := []string{
+		`{"time":"2020-10-15T18:09:00Z","repeated":7,"http":{"status":200},"message":"foo"}`,
+		`{"time":"2020-10-15T18:09:01Z","repeated":1,"http":{"status":200},"first_seen":"2020-10-15T18:09:00Z","last_seen":"2020-10-15T18:09:01Z","message":"foo"}`,
+	}
+
+	if buf.String() != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("unexpected output, expected:\n%s\nreceived:\n%s", strings.Join(expected, "\n"), buf.String())
+	}
+}
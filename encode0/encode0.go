@@ -157,3 +157,70 @@ func enc(dst io.Writer, idx int, r, oldr rune) (int, error) {
 
 	return idx, nil
 }
+
+// AppendBytes appends escaped src to the dst and returns the extended buffer.
+func AppendBytes(dst, src []byte) []byte {
+	var oldr rune
+
+	for idx := 0; idx < len(src); {
+		r, n := utf8.DecodeRune(src[idx:])
+
+		p, ok := codec[r]
+
+		if ok {
+			dst = append(dst, p...)
+
+		} else if r == '"' && oldr != '\\' {
+			dst = append(dst, '\\', '"')
+
+		} else {
+			dst = append(dst, src[idx:idx+n]...)
+		}
+
+		oldr = r
+		idx += n
+	}
+
+	return dst
+}
+
+// AppendRunes appends escaped src to the dst and returns the extended buffer.
+func AppendRunes(dst []byte, src []rune) []byte {
+	var oldr rune
+
+	for _, r := range src {
+		dst = appendRune(dst, r, oldr)
+		oldr = r
+	}
+
+	return dst
+}
+
+// AppendString appends escaped src to the dst and returns the extended buffer.
+func AppendString(dst []byte, src string) []byte {
+	var oldr rune
+
+	for _, r := range src {
+		dst = appendRune(dst, r, oldr)
+		oldr = r
+	}
+
+	return dst
+}
+
+func appendRune(dst []byte, r, oldr rune) []byte {
+	p, ok := codec[r]
+
+	if ok {
+		return append(dst, p...)
+
+	} else if r == '"' && oldr != '\\' {
+		return append(dst, '\\', '"')
+	}
+
+	var p0 [utf8.UTFMax]byte
+
+	n := utf8.EncodeRune(p0[:], r)
+
+	return append(dst, p0[:n]...)
+}
@@ -71,3 +71,51 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendBytes(t *testing.T) {
+	for in, expected := range codec {
+		in := in
+		expected := expected
+		t.Run(string(in), func(t *testing.T) {
+			t.Parallel()
+
+			p := AppendBytes(nil, []byte(string(in)))
+
+			if !bytes.Equal(p, expected) {
+				t.Errorf("expected: %s, recieved: %s", expected, p)
+			}
+		})
+	}
+}
+
+func TestAppendRunes(t *testing.T) {
+	for in, expected := range codec {
+		in := in
+		expected := expected
+		t.Run(string(in), func(t *testing.T) {
+			t.Parallel()
+
+			p := AppendRunes(nil, []rune{in})
+
+			if !bytes.Equal(p, expected) {
+				t.Errorf("expected: %s, recieved: %s", expected, p)
+			}
+		})
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	for in, expected := range codec {
+		in := in
+		expected := expected
+		t.Run(string(in), func(t *testing.T) {
+			t.Parallel()
+
+			p := AppendString(nil, string(in))
+
+			if !bytes.Equal(p, expected) {
+				t.Errorf("expected: %s, recieved: %s", expected, p)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0
+
+import (
+	"reflect"
+
+	"github.com/danil/log0/encode0"
+)
+
+// KVProvider is an error which contributes key-values to the log entry
+// when the error is written by the ErrorChain.
+type KVProvider interface {
+	KV() []KV
+}
+
+// chainDepth is a maximum depth of the error chain.
+const chainDepth = 32
+
+// ErrorChain returns error value which walks the chain of the wrapped errors
+// (Unwrap() error and Unwrap() []error) and which is marshaled to JSON object:
+// {"message":"foo: bar","type":"*fmt.wrapError","causes":[{"message":"bar","type":"*errors.errorString"}]}.
+// Key-values of the errors of the chain which implements the KVProvider interface
+// are written into the log entry, key-values of the wrapping errors
+// override key-values of the wrapped errors.
+func ErrorChain(v error) errorChainV { return errorChainV{V: v} }
+
+type errorChainV struct{ V error }
+
+func (v errorChainV) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v errorChainV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v errorChainV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v errorChainV) AppendText(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+	return append(dst, v.V.Error()...), nil
+}
+
+func (v errorChainV) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+	return appendChain(dst, v.V, chainDepth), nil
+}
+
+// appendChain appends JSON object of the error and of the wrapped errors.
+func appendChain(dst []byte, err error, depth int) []byte {
+	dst = append(dst, `{"message":"`...)
+	dst = encode0.AppendString(dst, err.Error())
+	dst = append(dst, `","type":"`...)
+	dst = encode0.AppendString(dst, reflect.TypeOf(err).String())
+	dst = append(dst, '"')
+
+	causes := unwrap(err)
+	if len(causes) != 0 && depth > 1 {
+		dst = append(dst, `,"causes":[`...)
+		first := true
+		for _, c := range causes {
+			if c == nil {
+				continue
+			}
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = appendChain(dst, c, depth-1)
+		}
+		dst = append(dst, ']')
+	}
+
+	return append(dst, '}')
+}
+
+// unwrap returns errors wrapped by the error.
+func unwrap(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		c := e.Unwrap()
+		if c == nil {
+			return nil
+		}
+		return []error{c}
+
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	}
+	return nil
+}
+
+// kvs assigns key-values of the errors of the chain
+// which implements the KVProvider interface.
+func (v errorChainV) kvs(enc *encoder) error {
+	if v.V == nil {
+		return nil
+	}
+	return chainKVs(enc, v.V, chainDepth)
+}
+
+func chainKVs(enc *encoder, err error, depth int) error {
+	if depth > 1 {
+		for _, c := range unwrap(err) {
+			if c == nil {
+				continue
+			}
+			e := chainKVs(enc, c, depth-1)
+			if e != nil {
+				return e
+			}
+		}
+	}
+
+	p, ok := err.(KVProvider)
+	if !ok {
+		return nil
+	}
+
+	return enc.kvs(p.KV())
+}
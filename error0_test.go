@@ -0,0 +1,171 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0_test
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/danil/log0"
+	"github.com/kinbiko/jsonassert"
+)
+
+type fieldsError struct {
+	msg string
+	kv  []log0.KV
+	err error
+}
+
+func (e fieldsError) Error() string { return e.msg }
+func (e fieldsError) KV() []log0.KV { return e.kv }
+func (e fieldsError) Unwrap() error { return e.err }
+
+type joinError struct{ errs []error }
+
+func (e joinError) Error() string {
+	var s []string
+	for _, err := range e.errs {
+		s = append(s, err.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+func (e joinError) Unwrap() []error { return e.errs }
+
+var ErrorChainTestCases = []struct {
+	name     string
+	line     int
+	kv       []log0.KV
+	expected string
+}{
+	{
+		name: "error without causes",
+		line: line(),
+		kv:   []log0.KV{log0.StringErrorChain("error", errors.New("foo"))},
+		expected: `{
+			"message":"Hello, World!",
+			"error":{"message":"foo","type":"*errors.errorString"}
+		}`,
+	},
+	{
+		name: "wrapped error",
+		line: line(),
+		kv:   []log0.KV{log0.StringErrorChain("error", fmt.Errorf("foo: %w", errors.New("bar")))},
+		expected: `{
+			"message":"Hello, World!",
+			"error":{
+				"message":"foo: bar",
+				"type":"*fmt.wrapError",
+				"causes":[{"message":"bar","type":"*errors.errorString"}]
+			}
+		}`,
+	},
+	{
+		name: "joined errors",
+		line: line(),
+		kv: []log0.KV{log0.StringErrorChain("error", joinError{errs: []error{
+			errors.New("foo"),
+			fmt.Errorf("bar: %w", errors.New("baz")),
+		}})},
+		expected: `{
+			"message":"Hello, World!",
+			"error":{
+				"message":"foo\nbar: baz",
+				"type":"log0_test.joinError",
+				"causes":[
+					{"message":"foo","type":"*errors.errorString"},
+					{
+						"message":"bar: baz",
+						"type":"*fmt.wrapError",
+						"causes":[{"message":"baz","type":"*errors.errorString"}]
+					}
+				]
+			}
+		}`,
+	},
+	{
+		name: "fields provider",
+		line: line(),
+		kv: []log0.KV{log0.StringErrorChain("error", fmt.Errorf("foo: %w", fieldsError{
+			msg: "bar",
+			kv:  []log0.KV{log0.StringInt("code", 42), log0.Strings("op", "read")},
+		}))},
+		expected: `{
+			"message":"Hello, World!",
+			"error":{
+				"message":"foo: bar",
+				"type":"*fmt.wrapError",
+				"causes":[{"message":"bar","type":"log0_test.fieldsError"}]
+			},
+			"code":42,
+			"op":"read"
+		}`,
+	},
+	{
+		name: "wrapping fields provider overrides wrapped fields provider",
+		line: line(),
+		kv: []log0.KV{log0.StringErrorChain("error", fieldsError{
+			msg: "foo",
+			kv:  []log0.KV{log0.StringInt("code", 1)},
+			err: fieldsError{
+				msg: "bar",
+				kv:  []log0.KV{log0.StringInt("code", 2), log0.Strings("op", "read")},
+			},
+		})},
+		expected: `{
+			"message":"Hello, World!",
+			"error":{
+				"message":"foo",
+				"type":"log0_test.fieldsError",
+				"causes":[{"message":"bar","type":"log0_test.fieldsError"}]
+			},
+			"code":1,
+			"op":"read"
+		}`,
+	},
+	{
+		name: "nil error",
+		line: line(),
+		kv:   []log0.KV{log0.StringErrorChain("error", nil)},
+		expected: `{
+			"message":"Hello, World!",
+			"error":null
+		}`,
+	},
+}
+
+func TestErrorChain(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range ErrorChainTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("error chain %s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			var buf bytes.Buffer
+
+			l := &log0.Log{
+				Output: &buf,
+				Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+			}
+
+			l1 := l.Get(tc.kv...)
+			defer l1.Put()
+
+			_, err := l1.Write([]byte("Hello, World!"))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			ja := jsonassert.New(testprinter{t: t, link: linkToExample})
+			ja.Assertf(buf.String(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gelf0 implements GELF transports
+// <https://docs.graylog.org/en/latest/pages/gelf.html>
+// for the entries of the log0.GELF formatter.
+package gelf0
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"sync"
+)
+
+// Compression methods.
+const (
+	Gzip = iota // Gzip is a gzip compression.
+	Zlib        // Zlib is a zlib compression.
+	None        // None is a no compression.
+)
+
+// trim returns entry without trailing newline.
+func trim(p []byte) []byte {
+	return bytes.TrimRight(p, "\n")
+}
+
+var (
+	gzipPool = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+	zlibPool = sync.Pool{New: func() interface{} { return zlib.NewWriter(nil) }}
+)
+
+// compress appends compressed entry to the dst.
+func compress(dst *bytes.Buffer, p []byte, method uint8) error {
+	switch method {
+	case Gzip:
+		w := gzipPool.Get().(*gzip.Writer)
+		defer gzipPool.Put(w)
+		w.Reset(dst)
+		_, err := w.Write(p)
+		if err != nil {
+			return err
+		}
+		return w.Close()
+
+	case Zlib:
+		w := zlibPool.Get().(*zlib.Writer)
+		defer zlibPool.Put(w)
+		w.Reset(dst)
+		_, err := w.Write(p)
+		if err != nil {
+			return err
+		}
+		return w.Close()
+	}
+
+	_, err := dst.Write(p)
+	return err
+}
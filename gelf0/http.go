@@ -0,0 +1,343 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gelf0
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrClosed is returned by the Write after the Close.
+	ErrClosed = errors.New("gelf0: writer is closed")
+	// ErrQueueFull is passed to the Error function with the batch dropped because of the undelivered batches.
+	ErrQueueFull = errors.New("gelf0: queue of the batches is full")
+	// ErrTimeout is returned by the Flush and Close if the batches are not delivered in time.
+	ErrTimeout = errors.New("gelf0: timeout")
+)
+
+// HTTPWriter posts batches of the newline delimited entries
+// to the GELF HTTP input (bulk receiving should be enabled
+// if the batch contains more than one entry).
+// Batch is posted in order by the background goroutine if its size exceeds
+// MaxBytes or if the Interval since the first entry of the batch expires.
+type HTTPWriter struct {
+	URL        string                        // URL is an endpoint of the GELF HTTP input, for example "http://graylog:12201/gelf".
+	Client     *http.Client                  // Client is a HTTP client, http.DefaultClient if nil.
+	Gzip       bool                          // Gzip compresses request bodies by gzip.
+	MaxBytes   int                           // MaxBytes is a maximum size of the batch, 1 MiB if zero.
+	Interval   time.Duration                 // Interval is a maximum delay of the entry, 1 second if zero.
+	Retries    int                           // Retries is a maximum number of the retries of the connection errors and 5xx responses, none if zero.
+	MinBackoff time.Duration                 // MinBackoff is an initial delay of the retry, 100 milliseconds if zero.
+	MaxBackoff time.Duration                 // MaxBackoff is a maximum delay of the retry, 30 seconds if zero.
+	Error      func(err error, batch []byte) // Error function receives delivery error and undelivered batch.
+
+	once    sync.Once
+	mu      sync.Mutex
+	buf     []byte
+	timer   *time.Timer
+	batches chan []byte
+	done    chan struct{}
+	pending int // pending is a number of the undelivered batches.
+	waiters []chan struct{}
+	err     error // err is a first delivery error since the last Flush.
+	closed  bool
+}
+
+func (w *HTTPWriter) start() {
+	w.batches = make(chan []byte, 16)
+	w.done = make(chan struct{})
+	go w.run()
+}
+
+func (w *HTTPWriter) run() {
+	defer close(w.done)
+	for batch := range w.batches {
+		err := w.deliver(batch)
+
+		w.mu.Lock()
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		w.pending--
+		if w.pending == 0 {
+			for _, c := range w.waiters {
+				close(c)
+			}
+			w.waiters = w.waiters[:0]
+		}
+		w.mu.Unlock()
+	}
+}
+
+func (w *HTTPWriter) maxBytes() int {
+	if w.MaxBytes <= 0 {
+		return 1 << 20
+	}
+	return w.MaxBytes
+}
+
+// Write appends the entry to the batch.
+// Delivery errors are not returned, see Error field and Flush.
+// Write does not wait for the delivery, the batch is dropped
+// if the queue of the 16 undelivered batches is full.
+func (w *HTTPWriter) Write(p []byte) (int, error) {
+	w.once.Do(w.start)
+
+	w.mu.Lock()
+
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+
+	var dropped [2][]byte
+
+	entry := trim(p)
+
+	if len(w.buf) != 0 && len(w.buf)+len(entry)+1 > w.maxBytes() {
+		dropped[0] = w.enqueue()
+	}
+
+	w.buf = append(append(w.buf, entry...), '\n')
+
+	if len(w.buf) >= w.maxBytes() {
+		dropped[1] = w.enqueue()
+		w.mu.Unlock()
+		w.drop(dropped[:])
+		return len(p), nil
+	}
+
+	if w.timer == nil {
+		interval := w.Interval
+		if interval <= 0 {
+			interval = time.Second
+		}
+		w.timer = time.AfterFunc(interval, w.expire)
+	}
+
+	w.mu.Unlock()
+	w.drop(dropped[:])
+
+	return len(p), nil
+}
+
+// expire enqueues the batch on the interval expiry.
+func (w *HTTPWriter) expire() {
+	var dropped []byte
+
+	w.mu.Lock()
+	if !w.closed {
+		dropped = w.enqueue()
+	}
+	w.mu.Unlock()
+
+	w.drop([][]byte{dropped})
+}
+
+// enqueue passes the batch to the background goroutine
+// without of waiting and returns the batch if the queue is full.
+func (w *HTTPWriter) enqueue() []byte {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	batch := w.buf
+	w.buf = nil
+
+	select {
+	case w.batches <- batch:
+		w.pending++
+		return nil
+	default:
+		if w.err == nil {
+			w.err = ErrQueueFull
+		}
+		return batch
+	}
+}
+
+// drop passes the dropped batches to the Error function.
+func (w *HTTPWriter) drop(batches [][]byte) {
+	for _, b := range batches {
+		if b != nil {
+			w.error(ErrQueueFull, b)
+		}
+	}
+}
+
+// deliver posts the batch and retries connection errors and 5xx responses.
+func (w *HTTPWriter) deliver(batch []byte) error {
+	body := batch
+
+	if w.Gzip {
+		var buf bytes.Buffer
+		err := compress(&buf, batch, Gzip)
+		if err != nil {
+			w.error(err, batch)
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	min, max := w.MinBackoff, w.MaxBackoff
+	if min == 0 {
+		min = 100 * time.Millisecond
+	}
+	if max == 0 {
+		max = 30 * time.Second
+	}
+
+	backoff := min
+
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(client, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt >= w.Retries {
+			w.error(err, batch)
+			return err
+		}
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > max {
+			backoff = max
+		}
+	}
+}
+
+// post posts the body and reports whether the error is retried.
+func (w *HTTPWriter) post(client *http.Client, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if w.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	return resp.StatusCode >= 500, fmt.Errorf("gelf0: unexpected HTTP status %s", resp.Status)
+}
+
+func (w *HTTPWriter) error(err error, batch []byte) {
+	if w.Error != nil {
+		w.Error(err, batch)
+	}
+}
+
+// Flush posts the batch, waits until all batches are delivered or failed
+// and returns the first delivery error since the last Flush.
+// No timeout if timeout is zero.
+func (w *HTTPWriter) Flush(timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	return w.flush(expired)
+}
+
+func (w *HTTPWriter) flush(expired <-chan time.Time) error {
+	w.once.Do(w.start)
+
+	var dropped []byte
+
+	w.mu.Lock()
+	if !w.closed {
+		dropped = w.enqueue()
+	}
+
+	var c chan struct{}
+	if w.pending != 0 {
+		c = make(chan struct{})
+		w.waiters = append(w.waiters, c)
+	}
+	w.mu.Unlock()
+
+	w.drop([][]byte{dropped})
+
+	if c != nil {
+		select {
+		case <-c:
+		case <-expired:
+			return ErrTimeout
+		}
+	}
+
+	w.mu.Lock()
+	err := w.err
+	w.err = nil
+	w.mu.Unlock()
+
+	return err
+}
+
+// Close flushes the batch, stops the background goroutine
+// and returns the first delivery error since the last Flush.
+// No timeout if timeout is zero.
+func (w *HTTPWriter) Close(timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	err := w.flush(expired)
+
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.batches)
+	}
+	w.mu.Unlock()
+
+	if err == ErrTimeout {
+		return err
+	}
+
+	select {
+	case <-w.done:
+	case <-expired:
+		return ErrTimeout
+	}
+
+	return err
+}
@@ -0,0 +1,258 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gelf0_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/gelf0"
+)
+
+// server is a GELF HTTP input which records request bodies.
+type server struct {
+	mu     sync.Mutex
+	bodies []string
+	status []int // status is a statuses of the responses, 202 if empty.
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+
+	p, _ := io.ReadAll(body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bodies = append(s.bodies, string(p))
+
+	status := http.StatusAccepted
+	if len(s.status) != 0 {
+		status, s.status = s.status[0], s.status[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (s *server) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func TestHTTPWriterBatchSize(t *testing.T) {
+	s := &server{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	w := &gelf0.HTTPWriter{URL: ts.URL, Gzip: true, MaxBytes: 40, Interval: time.Hour}
+
+	l := log0.GELF()
+	l.Output = w
+	l.Time = nil
+	l.KV = append(l.KV, log0.Strings("host", "example.tld"))
+
+	for _, msg := range []string{"foo", "bar", "baz"} {
+		_, err := l.Write([]byte(msg))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	err := w.Close(time.Second)
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	expected := []string{
+		`{"host":"example.tld","short_message":"foo","version":"1.1"}` + "\n",
+		`{"host":"example.tld","short_message":"bar","version":"1.1"}` + "\n",
+		`{"host":"example.tld","short_message":"baz","version":"1.1"}` + "\n",
+	}
+
+	if received := s.requests(); strings.Join(received, "|") != strings.Join(expected, "|") {
+		t.Errorf("unexpected requests, expected: %q, received: %q", expected, received)
+	}
+}
+
+func TestHTTPWriterBatchInterval(t *testing.T) {
+	s := &server{}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	w := &gelf0.HTTPWriter{URL: ts.URL, Interval: 10 * time.Millisecond}
+	defer w.Close(time.Second)
+
+	for _, p := range []string{"{\"a\":1}\n", "{\"b\":2}\n"} {
+		_, err := w.Write([]byte(p))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(s.requests()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if received := s.requests(); len(received) != 1 || received[0] != "{\"a\":1}\n{\"b\":2}\n" {
+		t.Errorf("unexpected requests: %q", received)
+	}
+}
+
+func TestHTTPWriterRetry(t *testing.T) {
+	s := &server{status: []int{http.StatusServiceUnavailable, http.StatusBadGateway}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	var errs []error
+
+	w := &gelf0.HTTPWriter{
+		URL:        ts.URL,
+		Retries:    2,
+		MinBackoff: time.Millisecond,
+		Error:      func(err error, batch []byte) { errs = append(errs, err) },
+	}
+
+	_, err := w.Write([]byte("{}\n"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Close(time.Second)
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	if n := len(s.requests()); n != 3 || len(errs) != 0 {
+		t.Errorf("unexpected number of the requests: %d, errors: %v", n, errs)
+	}
+}
+
+func TestHTTPWriterError(t *testing.T) {
+	s := &server{status: []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusInternalServerError}}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	var errs []string
+	var batches []string
+
+	w := &gelf0.HTTPWriter{
+		URL:        ts.URL,
+		Retries:    1,
+		MinBackoff: time.Millisecond,
+		Error: func(err error, batch []byte) {
+			errs = append(errs, err.Error())
+			batches = append(batches, string(batch))
+		},
+	}
+
+	_, err := w.Write([]byte("{\"a\":1}\n"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Flush(time.Second)
+	if err == nil || err.Error() != "gelf0: unexpected HTTP status 400 Bad Request" {
+		t.Errorf("unexpected flush error: %v", err)
+	}
+
+	_, err = w.Write([]byte("{\"b\":2}\n"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Close(time.Second)
+	if err == nil || err.Error() != "gelf0: unexpected HTTP status 500 Internal Server Error" {
+		t.Errorf("unexpected close error: %v", err)
+	}
+
+	expected := []string{"gelf0: unexpected HTTP status 400 Bad Request", "gelf0: unexpected HTTP status 500 Internal Server Error"}
+
+	if strings.Join(errs, "|") != strings.Join(expected, "|") || strings.Join(batches, "") != "{\"a\":1}\n{\"b\":2}\n" {
+		t.Errorf("unexpected errors: %q, batches: %q", errs, batches)
+	}
+
+	if n := len(s.requests()); n != 3 {
+		t.Errorf("unexpected number of the requests: %d", n)
+	}
+
+	_, err = w.Write([]byte("{}\n"))
+	if err != gelf0.ErrClosed {
+		t.Errorf("unexpected write error after close: %v", err)
+	}
+}
+
+func TestHTTPWriterHangingInput(t *testing.T) {
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	var mu sync.Mutex
+	var dropped int
+
+	w := &gelf0.HTTPWriter{
+		URL:      ts.URL,
+		MaxBytes: 1,
+		Error: func(err error, batch []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err == gelf0.ErrQueueFull {
+				dropped++
+			}
+		},
+	}
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for i := 0; i < 100; i++ {
+			_, err := w.Write([]byte("{}\n"))
+			if err != nil {
+				t.Errorf("unexpected write error: %s", err)
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("write is blocked by the hanging input")
+	}
+
+	mu.Lock()
+	n := dropped
+	mu.Unlock()
+
+	// At most one batch is posted and 16 batches are queued.
+	if n != 83 && n != 84 {
+		t.Errorf("unexpected number of the dropped batches: %d", n)
+	}
+
+	err := w.Close(10 * time.Millisecond)
+	if err != gelf0.ErrTimeout {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gelf0
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TCPWriter writes entries to the GELF TCP input delimited by the null byte
+// instead of the trailing newline. Unsent entries are buffered
+// and the next Write or Flush reconnects with exponential backoff,
+// the oldest unsent entries are dropped if the buffer is full.
+// Write blocks while connecting and writing, see async0.Writer.
+type TCPWriter struct {
+	dropped uint64 // dropped is a number of the dropped unsent entries.
+
+	Addr        string        // Addr is a "host:port" address of the GELF TCP input.
+	TLS         *tls.Config   // TLS is a TLS configuration, connection is not encrypted if nil.
+	Buffer      int           // Buffer is a maximum number of the unsent entries, 1024 if zero.
+	DialTimeout time.Duration // DialTimeout is a timeout of the connection, 10 seconds if zero.
+	MinBackoff  time.Duration // MinBackoff is an initial delay of the reconnection, 100 milliseconds if zero.
+	MaxBackoff  time.Duration // MaxBackoff is a maximum delay of the reconnection, 30 seconds if zero.
+
+	mu      sync.Mutex
+	conn    net.Conn
+	unsent  [][]byte // unsent is a buffer of the unsent entries from the oldest to the newest.
+	free    [][]byte // free is a buffers of the written entries for reuse.
+	backoff time.Duration
+	retry   time.Time // retry is a time of the next reconnection.
+}
+
+func (w *TCPWriter) timeout() time.Duration {
+	if w.DialTimeout == 0 {
+		return 10 * time.Second
+	}
+	return w.DialTimeout
+}
+
+func (w *TCPWriter) dial() (net.Conn, error) {
+	d := &net.Dialer{Timeout: w.timeout()}
+
+	if w.TLS != nil {
+		return tls.DialWithDialer(d, "tcp", w.Addr, w.TLS)
+	}
+
+	return d.Dial("tcp", w.Addr)
+}
+
+// fail closes the connection and schedules reconnection.
+func (w *TCPWriter) fail() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	min, max := w.MinBackoff, w.MaxBackoff
+	if min == 0 {
+		min = 100 * time.Millisecond
+	}
+	if max == 0 {
+		max = 30 * time.Second
+	}
+
+	if w.backoff == 0 {
+		w.backoff = min
+	} else {
+		w.backoff *= 2
+	}
+	if w.backoff > max {
+		w.backoff = max
+	}
+
+	w.retry = time.Now().Add(w.backoff)
+}
+
+// Write buffers the entry and writes all unsent entries.
+// Connection errors are not returned, see Flush.
+func (w *TCPWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	size := w.Buffer
+	if size <= 0 {
+		size = 1024
+	}
+
+	if len(w.unsent) >= size {
+		w.free = append(w.free, w.unsent[0])
+		w.unsent = w.unsent[1:]
+		atomic.AddUint64(&w.dropped, 1)
+	}
+
+	var b []byte
+	if n := len(w.free); n != 0 {
+		b, w.free = w.free[n-1][:0], w.free[:n-1]
+	}
+
+	b = append(append(b, trim(p)...), 0)
+	w.unsent = append(w.unsent, b)
+
+	if w.conn == nil && time.Now().Before(w.retry) {
+		return len(p), nil
+	}
+
+	_ = w.flush(time.Time{})
+
+	return len(p), nil
+}
+
+// flush connects and writes all unsent entries,
+// no deadline of the writes if deadline is zero.
+func (w *TCPWriter) flush(deadline time.Time) error {
+	if w.conn == nil {
+		conn, err := w.dial()
+		if err != nil {
+			w.fail()
+			return err
+		}
+		w.conn = conn
+		w.backoff = 0
+	}
+
+	if !deadline.IsZero() {
+		err := w.conn.SetWriteDeadline(deadline)
+		if err != nil {
+			w.fail()
+			return err
+		}
+	}
+
+	for len(w.unsent) != 0 {
+		_, err := w.conn.Write(w.unsent[0])
+		if err != nil {
+			w.fail()
+			return err
+		}
+		w.free = append(w.free, w.unsent[0])
+		w.unsent[0] = nil
+		w.unsent = w.unsent[1:]
+	}
+
+	return nil
+}
+
+// Flush connects regardless of the backoff and writes all unsent entries.
+func (w *TCPWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush(time.Time{})
+}
+
+// Unsent returns number of the unsent entries.
+func (w *TCPWriter) Unsent() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.unsent)
+}
+
+// Dropped returns number of the dropped unsent entries.
+func (w *TCPWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close connects regardless of the backoff, writes unsent entries
+// within the DialTimeout and closes the connection.
+// Entries which are not written are counted as dropped.
+func (w *TCPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	if len(w.unsent) != 0 {
+		err = w.flush(time.Now().Add(w.timeout()))
+	}
+
+	if n := len(w.unsent); n != 0 {
+		atomic.AddUint64(&w.dropped, uint64(n))
+		w.unsent = nil
+	}
+
+	if w.conn == nil {
+		return err
+	}
+
+	e := w.conn.Close()
+	if err == nil {
+		err = e
+	}
+	w.conn = nil
+
+	return err
+}
@@ -0,0 +1,282 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gelf0_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/gelf0"
+)
+
+// accept returns channel of the null byte delimited entries of the first connection.
+func accept(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+
+	c := make(chan string, 100)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(c)
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			s, err := r.ReadString(0)
+			if err != nil {
+				close(c)
+				return
+			}
+			c <- s
+		}
+	}()
+
+	return c
+}
+
+func next(t *testing.T, c <-chan string) string {
+	t.Helper()
+	select {
+	case s := <-c:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("entry is not received")
+	}
+	return ""
+}
+
+func TestTCPWriter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer ln.Close()
+
+	c := accept(t, ln)
+
+	w := &gelf0.TCPWriter{Addr: ln.Addr().String()}
+	defer w.Close()
+
+	l := log0.GELF()
+	l.Output = w
+	l.Time = nil
+	l.KV = append(l.KV, log0.Strings("host", "example.tld"))
+
+	_, err = l.Write([]byte("Hello, GELF!"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	_, err = l.Write([]byte("Hello,\nTCP!"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	expected := []string{
+		`{"host":"example.tld","short_message":"Hello, GELF!","version":"1.1"}` + "\x00",
+		`{"full_message":"Hello,\nTCP!","host":"example.tld","short_message":"Hello, TCP!","version":"1.1"}` + "\x00",
+	}
+
+	for _, e := range expected {
+		if s := next(t, c); s != e {
+			t.Errorf("unexpected entry, expected: %q, received: %q", e, s)
+		}
+	}
+}
+
+func TestTCPWriterTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected key error: %s", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected certificate error: %s", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unexpected certificate error: %s", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer ln.Close()
+
+	c := accept(t, ln)
+
+	w := &gelf0.TCPWriter{Addr: ln.Addr().String(), TLS: &tls.Config{RootCAs: pool}}
+	defer w.Close()
+
+	_, err = w.Write([]byte(`{"short_message":"Hello, TLS!"}` + "\n"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		t.Fatalf("unexpected flush error: %s", err)
+	}
+
+	if s := next(t, c); s != `{"short_message":"Hello, TLS!"}`+"\x00" {
+		t.Errorf("unexpected entry: %q", s)
+	}
+}
+
+func TestTCPWriterReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+
+	addr := ln.Addr().String()
+	ln.Close()
+
+	w := &gelf0.TCPWriter{Addr: addr, Buffer: 2, MinBackoff: time.Millisecond}
+	defer w.Close()
+
+	for _, s := range []string{"foo\n", "bar\n", "baz\n"} {
+		_, err = w.Write([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	if w.Unsent() != 2 || w.Dropped() != 1 {
+		t.Fatalf("unexpected number of the unsent entries: %d and dropped entries: %d", w.Unsent(), w.Dropped())
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("address is taken: %s", err)
+	}
+	defer ln.Close()
+
+	c := accept(t, ln)
+
+	err = w.Flush()
+	if err != nil {
+		t.Fatalf("unexpected flush error: %s", err)
+	}
+
+	_, err = w.Write([]byte("xyz\n"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	for _, e := range []string{"bar\x00", "baz\x00", "xyz\x00"} {
+		if s := next(t, c); s != e {
+			t.Errorf("unexpected entry, expected: %q, received: %q", e, s)
+		}
+	}
+
+	if w.Unsent() != 0 {
+		t.Errorf("unexpected number of the unsent entries: %d", w.Unsent())
+	}
+}
+
+func TestTCPWriterClose(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+
+	addr := ln.Addr().String()
+	ln.Close()
+
+	w := &gelf0.TCPWriter{Addr: addr, MaxBackoff: time.Hour}
+
+	for _, s := range []string{"foo\n", "bar\n"} {
+		_, err = w.Write([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	if w.Unsent() != 2 {
+		t.Fatalf("unexpected number of the unsent entries: %d", w.Unsent())
+	}
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("address is taken: %s", err)
+	}
+	defer ln.Close()
+
+	c := accept(t, ln)
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	for _, e := range []string{"foo\x00", "bar\x00"} {
+		if s := next(t, c); s != e {
+			t.Errorf("unexpected entry, expected: %q, received: %q", e, s)
+		}
+	}
+
+	if w.Unsent() != 0 || w.Dropped() != 0 {
+		t.Errorf("unexpected number of the unsent entries: %d and dropped entries: %d", w.Unsent(), w.Dropped())
+	}
+}
+
+func TestTCPWriterCloseDropped(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+
+	addr := ln.Addr().String()
+	ln.Close()
+
+	w := &gelf0.TCPWriter{Addr: addr}
+
+	for _, s := range []string{"foo\n", "bar\n"} {
+		_, err = w.Write([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	err = w.Close()
+	if err == nil {
+		t.Error("expected close error of the unavailable input")
+	}
+
+	if w.Unsent() != 0 || w.Dropped() != 2 {
+		t.Errorf("unexpected number of the unsent entries: %d and dropped entries: %d", w.Unsent(), w.Dropped())
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gelf0
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// Oversize policies of the UDP writer.
+const (
+	OversizeError = iota // OversizeError returns ErrTooLarge.
+	OversizeDrop         // OversizeDrop drops the entry without error.
+)
+
+const (
+	chunkHeader = 12  // chunkHeader is a size of the chunk header: magic, message ID, sequence number and sequence count.
+	maxChunks   = 128 // maxChunks is a maximum number of the chunks of the message.
+)
+
+// ErrTooLarge is returned if the compressed entry exceeds 128 chunks.
+var ErrTooLarge = errors.New("gelf0: message exceeds 128 chunks")
+
+// UDPWriter writes entries to the GELF UDP input,
+// entries which exceed the chunk size are chunked.
+type UDPWriter struct {
+	dropped uint64 // dropped is a number of the dropped oversized entries.
+
+	Addr        string // Addr is a "host:port" address of the GELF UDP input.
+	Compression uint8  // Compression is a compression method: all except known = gzip; 1 = zlib; 2 = none.
+	ChunkSize   int    // ChunkSize is a maximum size of the datagram including chunk header, 1420 if zero.
+	Oversize    uint8  // Oversize is a policy of the entries which exceed 128 chunks: all except 1 = error; 1 = drop.
+
+	mu   sync.Mutex
+	conn net.Conn
+	id   uint64 // id is a last message ID.
+	buf  bytes.Buffer
+	dgm  []byte // dgm is a chunk datagram.
+}
+
+func (w *UDPWriter) chunkSize() int {
+	if w.ChunkSize <= chunkHeader {
+		return 1420
+	}
+	return w.ChunkSize
+}
+
+// Write compresses the entry and writes it as a single datagram or as a chunks.
+func (w *UDPWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		conn, err := net.Dial("udp", w.Addr)
+		if err != nil {
+			return 0, err
+		}
+		w.conn = conn
+
+		var seed [8]byte
+		_, _ = rand.Read(seed[:])
+		w.id = binary.BigEndian.Uint64(seed[:])
+	}
+
+	w.buf.Reset()
+
+	err := compress(&w.buf, trim(p), w.Compression)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := w.buf.Bytes()
+	size := w.chunkSize()
+
+	if len(msg) <= size {
+		_, err = w.conn.Write(msg)
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	payload := size - chunkHeader
+	count := (len(msg) + payload - 1) / payload
+
+	if count > maxChunks {
+		if w.Oversize == OversizeDrop {
+			atomic.AddUint64(&w.dropped, 1)
+			return len(p), nil
+		}
+		return 0, ErrTooLarge
+	}
+
+	w.id++
+
+	for i := 0; i < count; i++ {
+		chunk := msg[i*payload:]
+		if len(chunk) > payload {
+			chunk = chunk[:payload]
+		}
+
+		w.dgm = append(w.dgm[:0], 0x1e, 0x0f)
+		w.dgm = append(w.dgm, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(w.dgm[2:10], w.id)
+		w.dgm = append(w.dgm, byte(i), byte(count))
+		w.dgm = append(w.dgm, chunk...)
+
+		_, err = w.conn.Write(w.dgm)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns number of the dropped oversized entries.
+func (w *UDPWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close closes the connection.
+func (w *UDPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gelf0_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/gelf0"
+	"github.com/kinbiko/jsonassert"
+)
+
+func listenUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive returns next datagram.
+func receive(t *testing.T, conn net.PacketConn) []byte {
+	t.Helper()
+	p := make([]byte, 65536)
+	err := conn.SetReadDeadline(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected deadline error: %s", err)
+	}
+	n, _, err := conn.ReadFrom(p)
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+	return p[:n]
+}
+
+func decompress(t *testing.T, p []byte, method uint8) string {
+	t.Helper()
+	var r io.Reader = bytes.NewReader(p)
+	var err error
+	switch method {
+	case gelf0.Gzip:
+		r, err = gzip.NewReader(r)
+	case gelf0.Zlib:
+		r, err = zlib.NewReader(r)
+	}
+	if err != nil {
+		t.Fatalf("unexpected decompression error: %s", err)
+	}
+	s, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+	return string(s)
+}
+
+var UDPTestCases = []struct {
+	name        string
+	compression uint8
+}{
+	{name: "gzip", compression: gelf0.Gzip},
+	{name: "zlib", compression: gelf0.Zlib},
+	{name: "none", compression: gelf0.None},
+}
+
+func TestUDPWriter(t *testing.T) {
+	for _, tc := range UDPTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := listenUDP(t)
+
+			w := &gelf0.UDPWriter{Addr: conn.LocalAddr().String(), Compression: tc.compression}
+			defer w.Close()
+
+			l := log0.GELF()
+			l.Output = w
+			l.Time = nil
+			l.KV = append(l.KV, log0.Strings("host", "example.tld"))
+
+			_, err := l.Write([]byte("Hello,\nGELF!"))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			ja := jsonassert.New(t)
+			ja.Assertf(decompress(t, receive(t, conn), tc.compression), `{
+				"version":"1.1",
+				"host":"example.tld",
+				"short_message":"Hello, GELF!",
+				"full_message":"Hello,\nGELF!"
+			}`)
+		})
+	}
+}
+
+func TestUDPWriterChunks(t *testing.T) {
+	for _, tc := range UDPTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := listenUDP(t)
+
+			w := &gelf0.UDPWriter{Addr: conn.LocalAddr().String(), Compression: tc.compression, ChunkSize: 64}
+			defer w.Close()
+
+			msg := `{"version":"1.1","short_message":"` + strings.Repeat("Hello, GELF! ", 100) + `"}`
+
+			_, err := w.Write([]byte(msg + "\n"))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			var id []byte
+			var payload []byte
+			var count int
+
+			for i := 0; i == 0 || i < count; i++ {
+				p := receive(t, conn)
+
+				if len(p) > 64 || len(p) < 12 || p[0] != 0x1e || p[1] != 0x0f {
+					t.Fatalf("unexpected chunk: %x", p)
+				}
+
+				if i == 0 {
+					id = append(id, p[2:10]...)
+					count = int(p[11])
+				}
+
+				if !bytes.Equal(p[2:10], id) || int(p[10]) != i || int(p[11]) != count {
+					t.Fatalf("unexpected chunk header: %x, message id: %x, sequence number: %d, count: %d", p[:12], id, i, count)
+				}
+
+				payload = append(payload, p[12:]...)
+			}
+
+			if count < 2 || count > 128 {
+				t.Errorf("unexpected number of the chunks: %d", count)
+			}
+
+			if s := decompress(t, payload, tc.compression); s != msg {
+				t.Errorf("unexpected message, expected: %q, received: %q", msg, s)
+			}
+		})
+	}
+}
+
+func TestUDPWriterOversize(t *testing.T) {
+	conn := listenUDP(t)
+
+	w := &gelf0.UDPWriter{Addr: conn.LocalAddr().String(), Compression: gelf0.None, ChunkSize: 13}
+	defer w.Close()
+
+	msg := []byte(strings.Repeat("x", 129))
+
+	_, err := w.Write(msg)
+	if !errors.Is(err, gelf0.ErrTooLarge) {
+		t.Errorf("unexpected write error: %v", err)
+	}
+
+	w.Oversize = gelf0.OversizeDrop
+
+	n, err := w.Write(msg)
+	if err != nil || n != len(msg) {
+		t.Errorf("unexpected write result: %d, %v", n, err)
+	}
+
+	if w.Dropped() != 1 {
+		t.Errorf("unexpected number of the dropped entries: %d", w.Dropped())
+	}
+
+	_, err = w.Write([]byte(strings.Repeat("y", 128)))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	p := receive(t, conn)
+	if p[10] != 0 || p[11] != 128 || string(p[12:]) != "y" {
+		t.Errorf("unexpected first chunk: %x", p)
+	}
+}
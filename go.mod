@@ -4,8 +4,7 @@ go 1.16
 
 require (
 	github.com/danil/equal4 v0.9.0
-	github.com/json-iterator/go v1.1.10
+	github.com/go-logr/logr v1.2.4
 	github.com/kinbiko/jsonassert v1.0.1
-	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
-	github.com/modern-go/reflect2 v1.0.1 // indirect
+	golang.org/x/sys v0.7.0
 )
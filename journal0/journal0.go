@@ -0,0 +1,329 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+// Package journal0 implements systemd-journald writer of the native protocol
+// <https://systemd.io/JOURNAL_NATIVE_PROTOCOL/>
+// for the log0 entries.
+package journal0
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/danil/log0"
+	"golang.org/x/sys/unix"
+)
+
+// Socket is a path of the journald native protocol socket.
+const Socket = "/run/systemd/journal/socket"
+
+// maxName is a maximum length of the field name.
+const maxName = 64
+
+// Writer writes entries to the journald as a datagrams of the native protocol.
+// Top-level fields of the JSON object of the entry are written
+// as a journal fields named by the uppercase keys
+// except the message field which is written as a MESSAGE,
+// the entry is a MESSAGE if it is not a JSON object.
+// Oversized entries are passed to the journald as a file descriptor
+// of the sealed memory file or of the unlinked temporary file
+// if the memory files are not supported.
+type Writer struct {
+	Addr        string     // Addr is a path of the journald socket, "/run/systemd/journal/socket" if empty.
+	Level       log0.Level // Level is a severity level of the entries of the Write, info if zero.
+	Identifier  string     // Identifier is a SYSLOG_IDENTIFIER field, base name of the executable if empty.
+	MessageKeys []string   // MessageKeys are keys of the MESSAGE field, "message" and "short_message" if nil.
+
+	once       sync.Once
+	severity   func(string) io.Writer
+	identifier string
+
+	mu   sync.Mutex
+	conn *net.UnixConn
+	addr *net.UnixAddr
+	buf  []byte // buf is a datagram of the entry.
+}
+
+func (w *Writer) init() {
+	w.severity = log0.SeverityWriters(w.write)
+
+	w.identifier = w.Identifier
+	if w.identifier == "" && len(os.Args) != 0 {
+		w.identifier = filepath.Base(os.Args[0])
+	}
+}
+
+// Severity returns a writer of the entries with the PRIORITY
+// of the severity level, see log0.SeverityWriters.
+func (w *Writer) Severity(severity string) io.Writer {
+	w.once.Do(w.init)
+	return w.severity(severity)
+}
+
+// Write writes the entry with the severity level of the Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	level := w.Level
+	if !level.Valid() {
+		level = log0.LevelInfo
+	}
+	return w.write(level, p)
+}
+
+func (w *Writer) write(level log0.Level, p []byte) (int, error) {
+	w.once.Do(w.init)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		// Socket is not connected for the passing of the file descriptors.
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+		if err != nil {
+			return 0, err
+		}
+
+		addr := w.Addr
+		if addr == "" {
+			addr = Socket
+		}
+
+		w.conn = conn
+		w.addr = &net.UnixAddr{Name: addr, Net: "unixgram"}
+	}
+
+	w.buf = w.append(w.buf[:0], level, bytes.TrimRight(p, "\n"))
+
+	_, _, err := w.conn.WriteMsgUnix(w.buf, nil, w.addr)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		err = w.send(w.buf)
+	}
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// send passes the file descriptor of the datagram to the journald.
+func (w *Writer) send(p []byte) error {
+	f, err := memfd(p)
+	if err != nil {
+		f, err = tempfile(p)
+		if err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+
+	_, _, err = w.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), w.addr)
+
+	return err
+}
+
+// memfd returns memory file of the datagram sealed against modification.
+func memfd(p []byte) (*os.File, error) {
+	fd, err := unix.MemfdCreate("journal0", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return nil, err
+	}
+
+	f := os.NewFile(uintptr(fd), "journal0")
+
+	_, err = f.Write(p)
+	if err == nil {
+		_, err = unix.FcntlInt(f.Fd(), unix.F_ADD_SEALS, unix.F_SEAL_SHRINK|unix.F_SEAL_GROW|unix.F_SEAL_WRITE|unix.F_SEAL_SEAL)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// tempfile returns unlinked temporary file of the datagram.
+func tempfile(p []byte) (*os.File, error) {
+	f, err := os.CreateTemp("/dev/shm", "journal0-")
+	if err != nil {
+		f, err = os.CreateTemp("", "journal0-")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = os.Remove(f.Name())
+	if err == nil {
+		_, err = f.Write(p)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// append appends datagram of the entry to the dst.
+func (w *Writer) append(dst []byte, level log0.Level, p []byte) []byte {
+	dst = append(dst, "PRIORITY="...)
+	dst = strconv.AppendInt(dst, int64(level.Syslog()), 10)
+	dst = append(dst, '\n')
+
+	if w.identifier != "" {
+		dst = appendField(dst, []byte("SYSLOG_IDENTIFIER"), []byte(w.identifier))
+	}
+
+	keys := w.MessageKeys
+	if keys == nil {
+		keys = messageKeys
+	}
+
+	begin := len(dst)
+
+	dst, ok := appendFields(dst, keys, p)
+	if !ok {
+		dst = appendField(dst[:begin], []byte("MESSAGE"), p)
+	}
+
+	return dst
+}
+
+var messageKeys = []string{"message", "short_message"}
+
+// appendFields appends fields of the top-level keys of the JSON object to the dst,
+// the first field of the message keys is a MESSAGE,
+// reports whether the entry is a JSON object.
+func appendFields(dst []byte, keys []string, p []byte) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('{') {
+		return dst, false
+	}
+
+	var (
+		name    []byte
+		message bool
+	)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return dst, false
+		}
+
+		var raw json.RawMessage
+
+		err = dec.Decode(&raw)
+		if err != nil {
+			return dst, false
+		}
+
+		key, _ := tok.(string)
+
+		if !message && contains(keys, key) {
+			name = append(name[:0], "MESSAGE"...)
+			message = true
+		} else {
+			name = appendName(name[:0], key)
+			if len(name) == 0 || string(name) == "PRIORITY" || string(name) == "MESSAGE" {
+				continue
+			}
+		}
+
+		var s string
+		if len(raw) != 0 && raw[0] == '"' && json.Unmarshal(raw, &s) == nil {
+			dst = appendField(dst, name, []byte(s))
+		} else {
+			dst = appendField(dst, name, raw)
+		}
+	}
+
+	return dst, true
+}
+
+func contains(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// appendName appends uppercase field name of the key
+// with characters other than letters, digits and underscores replaced by underscore
+// and leading underscores and digits removed.
+func appendName(dst []byte, key string) []byte {
+	for i := 0; i < len(key) && len(dst) < maxName; i++ {
+		c := key[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+			c -= 'a' - 'A'
+		case 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' || c == '_':
+			if len(dst) == 0 {
+				continue
+			}
+		default:
+			if len(dst) == 0 {
+				continue
+			}
+			c = '_'
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// appendField appends field to the dst,
+// value with newlines is prefixed by the little-endian 64-bit length.
+func appendField(dst, name, value []byte) []byte {
+	dst = append(dst, name...)
+
+	if bytes.IndexByte(value, '\n') < 0 {
+		dst = append(dst, '=')
+		dst = append(dst, value...)
+		return append(dst, '\n')
+	}
+
+	dst = append(dst, '\n')
+
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(value)))
+
+	dst = append(dst, n[:]...)
+	dst = append(dst, value...)
+
+	return append(dst, '\n')
+}
+
+// Close closes the socket.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
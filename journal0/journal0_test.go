@@ -0,0 +1,261 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package journal0_test
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/journal0"
+	"golang.org/x/sys/unix"
+)
+
+func line() int { _, _, l, _ := runtime.Caller(1); return l }
+
+// listen returns a listener of the journald socket in the temporary directory.
+func listen(t *testing.T) *net.UnixConn {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "journal0")
+	if err != nil {
+		t.Fatalf("unexpected temp dir error: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: filepath.Join(dir, "socket"), Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// receive returns next datagram or content of the passed file.
+func receive(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	s, _ := receiveSeals(t, conn)
+	return s
+}
+
+// receiveSeals returns next datagram or content and seals of the passed file,
+// seals are -1 if the file is not passed.
+func receiveSeals(t *testing.T, conn *net.UnixConn) (string, int) {
+	t.Helper()
+
+	p := make([]byte, 65536)
+	oob := make([]byte, syscall.CmsgSpace(4))
+
+	err := conn.SetReadDeadline(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected deadline error: %s", err)
+	}
+
+	n, oobn, _, _, err := conn.ReadMsgUnix(p, oob)
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+
+	if oobn == 0 {
+		return string(p[:n]), -1
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("unexpected control message: %v %s", msgs, err)
+	}
+
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("unexpected rights: %v %s", fds, err)
+	}
+
+	f := os.NewFile(uintptr(fds[0]), "journal")
+	defer f.Close()
+
+	seals, err := unix.FcntlInt(f.Fd(), unix.F_GET_SEALS, 0)
+	if err != nil {
+		t.Fatalf("unexpected seals error: %s", err)
+	}
+
+	b, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<30))
+	if err != nil {
+		t.Fatalf("unexpected file read error: %s", err)
+	}
+
+	return string(b), seals
+}
+
+var WriterTestCases = []struct {
+	name     string
+	line     int
+	writer   *journal0.Writer
+	input    string
+	expected string
+}{
+	{
+		name:     "message",
+		line:     line(),
+		writer:   &journal0.Writer{},
+		input:    `{"message":"Hello, journal!"}` + "\n",
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nMESSAGE=Hello, journal!\n",
+	},
+	{
+		name:   "fields",
+		line:   line(),
+		writer: &journal0.Writer{Level: log0.LevelError},
+		input:  `{"message":"Hello, journal!","request-id":"abc","count":3,"ok":true,"obj":{"a":1},"_trusted":"x","0day":"y","priority":"7","":"z"}`,
+		expected: "PRIORITY=3\nSYSLOG_IDENTIFIER=app\nMESSAGE=Hello, journal!\nREQUEST_ID=abc\nCOUNT=3\nOK=true\n" +
+			"OBJ={\"a\":1}\nTRUSTED=x\nDAY=y\n",
+	},
+	{
+		name:     "multiline value",
+		line:     line(),
+		writer:   &journal0.Writer{},
+		input:    `{"message":"Hello,\njournal!"}`,
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nMESSAGE\n\x0f\x00\x00\x00\x00\x00\x00\x00Hello,\njournal!\n",
+	},
+	{
+		name:     "without message",
+		line:     line(),
+		writer:   &journal0.Writer{},
+		input:    `{"excerpt":"Hello, journal!"}`,
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nEXCERPT=Hello, journal!\n",
+	},
+	{
+		name:     "short message",
+		line:     line(),
+		writer:   &journal0.Writer{},
+		input:    `{"version":"1.1","short_message":"Hello, journal!","full_message":"Hello,\njournal!"}`,
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nVERSION=1.1\nMESSAGE=Hello, journal!\nFULL_MESSAGE\n\x0f\x00\x00\x00\x00\x00\x00\x00Hello,\njournal!\n",
+	},
+	{
+		name:     "message keys",
+		line:     line(),
+		writer:   &journal0.Writer{MessageKeys: []string{"msg"}},
+		input:    `{"Message":"foo","msg":"Hello, journal!","message":"bar"}`,
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nMESSAGE=Hello, journal!\n",
+	},
+	{
+		name:     "plain text",
+		line:     line(),
+		writer:   &journal0.Writer{},
+		input:    "Hello, journal!\n",
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nMESSAGE=Hello, journal!\n",
+	},
+	{
+		name:     "malformed JSON",
+		line:     line(),
+		writer:   &journal0.Writer{},
+		input:    `{"message":"Hello, journal!"`,
+		expected: "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nMESSAGE={\"message\":\"Hello, journal!\"\n",
+	},
+}
+
+func TestWriter(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range WriterTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			conn := listen(t)
+
+			w := tc.writer
+			w.Addr = conn.LocalAddr().String()
+			w.Identifier = "app"
+			defer w.Close()
+
+			n, err := w.Write([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s %s", err, linkToExample)
+			}
+			if n != len(tc.input) {
+				t.Errorf("unexpected write length, expected: %d, recieved: %d %s", len(tc.input), n, linkToExample)
+			}
+
+			if s := receive(t, conn); s != tc.expected {
+				t.Errorf("unexpected datagram, expected: %q, recieved: %q %s", tc.expected, s, linkToExample)
+			}
+		})
+	}
+}
+
+func TestWriterSeverity(t *testing.T) {
+	conn := listen(t)
+
+	w := &journal0.Writer{Addr: conn.LocalAddr().String(), Identifier: "app"}
+	defer w.Close()
+
+	l := &log0.Log{
+		Output:   w,
+		Severity: w.Severity,
+		Keys:     [4]encoding.TextMarshaler{log0.String("message")},
+	}
+
+	err := l.Warning("Hello, journal!")
+	if err != nil {
+		t.Fatalf("unexpected log error: %s", err)
+	}
+
+	expected := "PRIORITY=4\nSYSLOG_IDENTIFIER=app\nMESSAGE=Hello, journal!\nSEVERITY=4\n"
+	if s := receive(t, conn); s != expected {
+		t.Errorf("unexpected datagram, expected: %q, recieved: %q", expected, s)
+	}
+
+	if w.Severity("foo") != nil {
+		t.Error("unexpected writer of the unknown severity")
+	}
+}
+
+func TestWriterLarge(t *testing.T) {
+	conn := listen(t)
+
+	w := &journal0.Writer{Addr: conn.LocalAddr().String(), Identifier: "app"}
+	defer w.Close()
+
+	msg := strings.Repeat("Hello, journal! ", 1<<16)
+
+	_, err := w.Write([]byte(`{"message":"` + msg + `"}`))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	expected := "PRIORITY=6\nSYSLOG_IDENTIFIER=app\nMESSAGE=" + msg + "\n"
+
+	// Oversized datagram fails with EMSGSIZE and is passed as a sealed memory file.
+	s, seals := receiveSeals(t, conn)
+	if s != expected {
+		t.Errorf("unexpected datagram of the length %d, expected length: %d", len(s), len(expected))
+	}
+
+	sealed := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+	if seals != sealed {
+		t.Errorf("unexpected seals of the passed file, expected: %#x, recieved: %#x", sealed, seals)
+	}
+}
+
+func TestWriterNoSocket(t *testing.T) {
+	w := &journal0.Writer{Addr: filepath.Join(os.TempDir(), "journal0-nonexistent", "socket")}
+
+	_, err := w.Write([]byte("foo"))
+	if err == nil {
+		t.Error("unexpected write success")
+	}
+}
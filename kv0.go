@@ -20,6 +20,9 @@ type kvjt struct {
 func (kv kvjt) MarshalText() (text []byte, err error) { return kv.K.MarshalText() }
 func (kv kvjt) MarshalJSON() ([]byte, error)          { return kv.V.MarshalJSON() }
 
+func (kv kvjt) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+func (kv kvjt) AppendJSON(dst []byte) ([]byte, error) { return appendJSON(dst, kv.V) }
+
 func StringBool(k string, v bool) kvjt {
 	return kvjt{K: String(k), V: Bool(v)}
 }
@@ -215,6 +218,114 @@ func StringReflect(k string, v interface{}) kvjt {
 	return kvjt{K: String(k), V: Reflect(v)}
 }
 
+func StringObject(k string, v ...KV) kvjt {
+	return kvjt{K: String(k), V: Object(v...)}
+}
+
+func StringNamespace(k string) kvns {
+	return kvns{K: String(k)}
+}
+
+func StringBoolSlice(k string, v []bool) kvjt {
+	return kvjt{K: String(k), V: BoolSlice(v)}
+}
+
+func StringComplex128Slice(k string, v []complex128) kvjt {
+	return kvjt{K: String(k), V: Complex128Slice(v)}
+}
+
+func StringComplex64Slice(k string, v []complex64) kvjt {
+	return kvjt{K: String(k), V: Complex64Slice(v)}
+}
+
+func StringDurationSlice(k string, v []time.Duration) kvjt {
+	return kvjt{K: String(k), V: DurationSlice(v)}
+}
+
+func StringErrorChain(k string, v error) kvjt {
+	return kvjt{K: String(k), V: ErrorChain(v)}
+}
+
+func StringErrorStack(k string, v error) kvjt {
+	return kvjt{K: String(k), V: errorStackV{V: v, S: callers(StackFrames)}}
+}
+
+func StringErrorStackText(k string, v error) kvjt {
+	return kvjt{K: String(k), V: errorStackV{V: v, S: callers(StackText)}}
+}
+
+func StringErrorSlice(k string, v []error) kvjt {
+	return kvjt{K: String(k), V: ErrorSlice(v)}
+}
+
+func StringFloat32Slice(k string, v []float32) kvjt {
+	return kvjt{K: String(k), V: Float32Slice(v)}
+}
+
+func StringFloat64Slice(k string, v []float64) kvjt {
+	return kvjt{K: String(k), V: Float64Slice(v)}
+}
+
+func StringIntSlice(k string, v []int) kvjt {
+	return kvjt{K: String(k), V: IntSlice(v)}
+}
+
+func StringInt16Slice(k string, v []int16) kvjt {
+	return kvjt{K: String(k), V: Int16Slice(v)}
+}
+
+func StringInt32Slice(k string, v []int32) kvjt {
+	return kvjt{K: String(k), V: Int32Slice(v)}
+}
+
+func StringInt64Slice(k string, v []int64) kvjt {
+	return kvjt{K: String(k), V: Int64Slice(v)}
+}
+
+func StringInt8Slice(k string, v []int8) kvjt {
+	return kvjt{K: String(k), V: Int8Slice(v)}
+}
+
+func StringStringSlice(k string, v []string) kvjt {
+	return kvjt{K: String(k), V: StringSlice(v)}
+}
+
+func StringTextSlice(k string, v []encoding.TextMarshaler) kvjt {
+	return kvjt{K: String(k), V: TextSlice(v)}
+}
+
+func StringTimeSlice(k string, v []time.Time) kvjt {
+	return kvjt{K: String(k), V: TimeSlice(v)}
+}
+
+func StringUintSlice(k string, v []uint) kvjt {
+	return kvjt{K: String(k), V: UintSlice(v)}
+}
+
+func StringUint16Slice(k string, v []uint16) kvjt {
+	return kvjt{K: String(k), V: Uint16Slice(v)}
+}
+
+func StringUint32Slice(k string, v []uint32) kvjt {
+	return kvjt{K: String(k), V: Uint32Slice(v)}
+}
+
+func StringUint64Slice(k string, v []uint64) kvjt {
+	return kvjt{K: String(k), V: Uint64Slice(v)}
+}
+
+func StringUint8Slice(k string, v []uint8) kvjt {
+	return kvjt{K: String(k), V: Uint8Slice(v)}
+}
+
+func StringUintptrSlice(k string, v []uintptr) kvjt {
+	return kvjt{K: String(k), V: UintptrSlice(v)}
+}
+
+func StringArray(k string, v ...json.Marshaler) kvjt {
+	return kvjt{K: String(k), V: Array(v...)}
+}
+
 func TextBool(k encoding.TextMarshaler, v bool) kvjt {
 	return kvjt{K: k, V: Bool(v)}
 }
@@ -414,6 +525,127 @@ func TextReflect(k encoding.TextMarshaler, v interface{}) kvjt {
 	return kvjt{K: k, V: Reflect(v)}
 }
 
+func TextObject(k encoding.TextMarshaler, v ...KV) kvjt {
+	return kvjt{K: k, V: Object(v...)}
+}
+
+func TextNamespace(k encoding.TextMarshaler) kvns {
+	return kvns{K: k}
+}
+
+func TextBoolSlice(k encoding.TextMarshaler, v []bool) kvjt {
+	return kvjt{K: k, V: BoolSlice(v)}
+}
+
+func TextComplex128Slice(k encoding.TextMarshaler, v []complex128) kvjt {
+	return kvjt{K: k, V: Complex128Slice(v)}
+}
+
+func TextComplex64Slice(k encoding.TextMarshaler, v []complex64) kvjt {
+	return kvjt{K: k, V: Complex64Slice(v)}
+}
+
+func TextDurationSlice(k encoding.TextMarshaler, v []time.Duration) kvjt {
+	return kvjt{K: k, V: DurationSlice(v)}
+}
+
+func TextErrorChain(k encoding.TextMarshaler, v error) kvjt {
+	return kvjt{K: k, V: ErrorChain(v)}
+}
+
+func TextErrorStack(k encoding.TextMarshaler, v error) kvjt {
+	return kvjt{K: k, V: errorStackV{V: v, S: callers(StackFrames)}}
+}
+
+func TextErrorStackText(k encoding.TextMarshaler, v error) kvjt {
+	return kvjt{K: k, V: errorStackV{V: v, S: callers(StackText)}}
+}
+
+func TextErrorSlice(k encoding.TextMarshaler, v []error) kvjt {
+	return kvjt{K: k, V: ErrorSlice(v)}
+}
+
+func TextFloat32Slice(k encoding.TextMarshaler, v []float32) kvjt {
+	return kvjt{K: k, V: Float32Slice(v)}
+}
+
+func TextFloat64Slice(k encoding.TextMarshaler, v []float64) kvjt {
+	return kvjt{K: k, V: Float64Slice(v)}
+}
+
+func TextIntSlice(k encoding.TextMarshaler, v []int) kvjt {
+	return kvjt{K: k, V: IntSlice(v)}
+}
+
+func TextInt16Slice(k encoding.TextMarshaler, v []int16) kvjt {
+	return kvjt{K: k, V: Int16Slice(v)}
+}
+
+func TextInt32Slice(k encoding.TextMarshaler, v []int32) kvjt {
+	return kvjt{K: k, V: Int32Slice(v)}
+}
+
+func TextInt64Slice(k encoding.TextMarshaler, v []int64) kvjt {
+	return kvjt{K: k, V: Int64Slice(v)}
+}
+
+func TextInt8Slice(k encoding.TextMarshaler, v []int8) kvjt {
+	return kvjt{K: k, V: Int8Slice(v)}
+}
+
+func TextStringSlice(k encoding.TextMarshaler, v []string) kvjt {
+	return kvjt{K: k, V: StringSlice(v)}
+}
+
+func TextTextSlice(k encoding.TextMarshaler, v []encoding.TextMarshaler) kvjt {
+	return kvjt{K: k, V: TextSlice(v)}
+}
+
+func TextTimeSlice(k encoding.TextMarshaler, v []time.Time) kvjt {
+	return kvjt{K: k, V: TimeSlice(v)}
+}
+
+func TextUintSlice(k encoding.TextMarshaler, v []uint) kvjt {
+	return kvjt{K: k, V: UintSlice(v)}
+}
+
+func TextUint16Slice(k encoding.TextMarshaler, v []uint16) kvjt {
+	return kvjt{K: k, V: Uint16Slice(v)}
+}
+
+func TextUint32Slice(k encoding.TextMarshaler, v []uint32) kvjt {
+	return kvjt{K: k, V: Uint32Slice(v)}
+}
+
+func TextUint64Slice(k encoding.TextMarshaler, v []uint64) kvjt {
+	return kvjt{K: k, V: Uint64Slice(v)}
+}
+
+func TextUint8Slice(k encoding.TextMarshaler, v []uint8) kvjt {
+	return kvjt{K: k, V: Uint8Slice(v)}
+}
+
+func TextUintptrSlice(k encoding.TextMarshaler, v []uintptr) kvjt {
+	return kvjt{K: k, V: UintptrSlice(v)}
+}
+
+func TextArray(k encoding.TextMarshaler, v ...json.Marshaler) kvjt {
+	return kvjt{K: k, V: Array(v...)}
+}
+
+// kvns is a namespace key implements json/text marshaler.
+// Key-values which follows the namespace key are nested
+// into the JSON object of the namespace key.
+type kvns struct {
+	K encoding.TextMarshaler
+}
+
+func (kv kvns) MarshalText() (text []byte, err error) { return kv.K.MarshalText() }
+func (kv kvns) MarshalJSON() ([]byte, error)          { return []byte("{}"), nil }
+
+func (kv kvns) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+func (kv kvns) AppendJSON(dst []byte) ([]byte, error) { return append(dst, "{}"...), nil }
+
 // kvjts is a key-value pair implements text/json marshaler and stringer.
 // String method intends to indicate severity level.
 // For example syslog levels: "0" emergency;
@@ -435,6 +667,9 @@ func (kv kvjts) MarshalText() (text []byte, err error) { return kv.K.MarshalText
 func (kv kvjts) MarshalJSON() ([]byte, error)          { return kv.V.MarshalJSON() }
 func (kv kvjts) String() string                        { return kv.S.String() }
 
+func (kv kvjts) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+func (kv kvjts) AppendJSON(dst []byte) ([]byte, error) { return appendJSON(dst, kv.V) }
+
 func StringSeverity(k string, v string) kvjts {
 	return kvjts{K: String(k), V: String(v), S: String(v)}
 }
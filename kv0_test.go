@@ -4425,6 +4425,87 @@ var KVTestCases = []struct {
 			"reflect untyped nil":null
 		}`,
 	},
+	{
+		line:         line(),
+		input:        log0.StringStringSlice("string slice", []string{"foo", "bar\n\"baz\""}),
+		expected:     `["foo","bar\n\"baz\""]`,
+		expectedText: `["foo","bar\n\"baz\""]`,
+		expectedJSON: `{
+			"string slice":["foo","bar\n\"baz\""]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringStringSlice("nil string slice", nil),
+		expected:     "null",
+		expectedText: "null",
+		expectedJSON: `{
+			"nil string slice":null
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringStringSlice("empty string slice", []string{}),
+		expected:     "[]",
+		expectedText: "[]",
+		expectedJSON: `{
+			"empty string slice":[]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringInt64Slice("int64 slice", []int64{-42, 0, 42}),
+		expected:     "[-42,0,42]",
+		expectedText: "[-42,0,42]",
+		expectedJSON: `{
+			"int64 slice":[-42,0,42]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringErrorSlice("error slice", []error{errors.New("foo"), nil}),
+		expected:     `["foo",null]`,
+		expectedText: `["foo",null]`,
+		expectedJSON: `{
+			"error slice":["foo",null]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringTimeSlice("time slice", []time.Time{time.Date(1970, time.January, 1, 0, 0, 0, 42, time.UTC)}),
+		expected:     `["1970-01-01T00:00:00.000000042Z"]`,
+		expectedText: `["1970-01-01T00:00:00.000000042Z"]`,
+		expectedJSON: `{
+			"time slice":["1970-01-01T00:00:00.000000042Z"]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.TextBoolSlice(log0.String("bool slice"), []bool{true, false}),
+		expected:     "[true,false]",
+		expectedText: "[true,false]",
+		expectedJSON: `{
+			"bool slice":[true,false]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringDurationSlice("duration slice", []time.Duration{42 * time.Second}),
+		expected:     `["42s"]`,
+		expectedText: `["42s"]`,
+		expectedJSON: `{
+			"duration slice":["42s"]
+		}`,
+	},
+	{
+		line:         line(),
+		input:        log0.StringArray("array", log0.String("foo"), log0.Int(42), nil, log0.Object(log0.Strings("bar", "baz"))),
+		expected:     `["foo",42,null,{"bar":"baz"}]`,
+		expectedText: `["foo",42,null,{"bar":"baz"}]`,
+		expectedJSON: `{
+			"array":["foo",42,null,{"bar":"baz"}]
+		}`,
+	},
 }
 
 func TestKV(t *testing.T) {
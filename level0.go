@@ -0,0 +1,209 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Level is a syslog severity level
+// (https://en.wikipedia.org/wiki/Syslog#Severity_level).
+// Zero value is an undefined level.
+// The more severe level is the less level.
+type Level uint8
+
+const (
+	LevelEmergency Level = iota + 1 // "0" emergency
+	LevelAlert                      // "1" alert
+	LevelCritical                   // "2" critical
+	LevelError                      // "3" error
+	LevelWarning                    // "4" warning
+	LevelNotice                     // "5" notice
+	LevelInfo                       // "6" informational
+	LevelDebug                      // "7" debug
+)
+
+// Severity formats of the severity level values.
+const (
+	SeverityDigit  = iota // SeverityDigit is a syslog number as a string: "0"-"7".
+	SeverityNumber        // SeverityNumber is a syslog number: 0-7, for example GELF level.
+	SeverityName          // SeverityName is a lower case name: "emergency"-"debug", for example console output.
+	SeverityEnum          // SeverityEnum is an upper case enum name of the cloud logging schemas: "EMERGENCY"-"DEBUG" or "DEFAULT" if level is undefined.
+)
+
+var levelNames = [...]string{
+	LevelEmergency: "emergency",
+	LevelAlert:     "alert",
+	LevelCritical:  "critical",
+	LevelError:     "error",
+	LevelWarning:   "warning",
+	LevelNotice:    "notice",
+	LevelInfo:      "info",
+	LevelDebug:     "debug",
+}
+
+var levelEnums = [...]string{
+	"DEFAULT",
+	LevelEmergency: "EMERGENCY",
+	LevelAlert:     "ALERT",
+	LevelCritical:  "CRITICAL",
+	LevelError:     "ERROR",
+	LevelWarning:   "WARNING",
+	LevelNotice:    "NOTICE",
+	LevelInfo:      "INFO",
+	LevelDebug:     "DEBUG",
+}
+
+// levelAliases is an alternative names of the levels.
+var levelAliases = [...]struct {
+	name  string
+	level Level
+}{
+	{"emerg", LevelEmergency},
+	{"panic", LevelEmergency},
+	{"crit", LevelCritical},
+	{"err", LevelError},
+	{"warn", LevelWarning},
+	{"informational", LevelInfo},
+}
+
+// ParseLevel parses severity level from the syslog number "0"-"7"
+// or from the case insensitive name, including abbreviations
+// such as "emerg", "crit", "err", "warn".
+func ParseLevel(s string) (Level, error) {
+	if len(s) == 1 && s[0] >= '0' && s[0] <= '7' {
+		return Level(s[0]-'0') + LevelEmergency, nil
+	}
+
+	for l := LevelEmergency; l <= LevelDebug; l++ {
+		if strings.EqualFold(s, levelNames[l]) {
+			return l, nil
+		}
+	}
+
+	for _, a := range levelAliases {
+		if strings.EqualFold(s, a.name) {
+			return a.level, nil
+		}
+	}
+
+	return 0, fmt.Errorf("log0: unknown severity level %q", s)
+}
+
+// SeverityWriters returns a function for the Severity field of the Log
+// which returns a writer of the entries of the severity level by the write,
+// nil if the severity is not a level, see ParseLevel.
+func SeverityWriters(write func(level Level, p []byte) (int, error)) func(severity string) io.Writer {
+	var writers [LevelDebug + 1]levelWriter
+	for i := range writers {
+		writers[i] = levelWriter{level: Level(i), write: write}
+	}
+
+	return func(severity string) io.Writer {
+		level, err := ParseLevel(severity)
+		if err != nil {
+			return nil
+		}
+		return &writers[level]
+	}
+}
+
+// levelWriter is a writer of the entries of the severity level.
+type levelWriter struct {
+	level Level
+	write func(Level, []byte) (int, error)
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) { return w.write(w.level, p) }
+
+// Valid reports whether the level is one of the eight syslog levels.
+func (l Level) Valid() bool { return l >= LevelEmergency && l <= LevelDebug }
+
+// Syslog returns syslog number of the level: 0-7 or -1 if the level is undefined.
+func (l Level) Syslog() int {
+	if !l.Valid() {
+		return -1
+	}
+	return int(l - LevelEmergency)
+}
+
+// AtLeast reports whether the level is as severe as the level l0 or more severe.
+func (l Level) AtLeast(l0 Level) bool { return l.Valid() && l <= l0 }
+
+// String returns lower case name of the level.
+func (l Level) String() string {
+	if !l.Valid() {
+		return "Level(" + strconv.Itoa(int(l)) + ")"
+	}
+	return levelNames[l]
+}
+
+// Enum returns upper case enum name of the level used by the cloud logging schemas.
+func (l Level) Enum() string {
+	if !l.Valid() {
+		return levelEnums[0]
+	}
+	return levelEnums[l]
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	l0, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = l0
+	return nil
+}
+
+func (l Level) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(l.String())), nil
+}
+
+func (l Level) AppendText(dst []byte) ([]byte, error) {
+	return append(dst, l.String()...), nil
+}
+
+func (l Level) AppendJSON(dst []byte) ([]byte, error) {
+	return strconv.AppendQuote(dst, l.String()), nil
+}
+
+// Severity returns JSON marshaler of the level in the severity format.
+// All formats except known are the syslog number as a string.
+func (l Level) Severity(format uint8) json.Marshaler {
+	if !l.Valid() {
+		l = 0
+	}
+	if format > SeverityEnum {
+		format = SeverityDigit
+	}
+	return severities[format][l]
+}
+
+// severities is a preallocated level values of the each severity format.
+var severities = func() (a [SeverityEnum + 1][LevelDebug + 1]json.Marshaler) {
+	for l := Level(0); l <= LevelDebug; l++ {
+		a[SeverityDigit][l] = String(strconv.Itoa(l.Syslog()))
+		a[SeverityNumber][l] = Int(l.Syslog())
+		a[SeverityName][l] = String(l.String())
+		a[SeverityEnum][l] = String(l.Enum())
+	}
+	return a
+}()
+
+// digits is a preallocated syslog numbers of the levels as a strings.
+var digits = func() (a [LevelDebug + 1]fmt.Stringer) {
+	for l := Level(0); l <= LevelDebug; l++ {
+		a[l] = String(strconv.Itoa(l.Syslog()))
+	}
+	return a
+}()
@@ -0,0 +1,232 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0_test
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/danil/log0"
+	"github.com/kinbiko/jsonassert"
+)
+
+var ParseLevelTestCases = []struct {
+	line     int
+	input    string
+	expected log0.Level
+	err      bool
+}{
+	{line: line(), input: "0", expected: log0.LevelEmergency},
+	{line: line(), input: "3", expected: log0.LevelError},
+	{line: line(), input: "7", expected: log0.LevelDebug},
+	{line: line(), input: "emergency", expected: log0.LevelEmergency},
+	{line: line(), input: "EMERG", expected: log0.LevelEmergency},
+	{line: line(), input: "Critical", expected: log0.LevelCritical},
+	{line: line(), input: "crit", expected: log0.LevelCritical},
+	{line: line(), input: "err", expected: log0.LevelError},
+	{line: line(), input: "warn", expected: log0.LevelWarning},
+	{line: line(), input: "WARNING", expected: log0.LevelWarning},
+	{line: line(), input: "informational", expected: log0.LevelInfo},
+	{line: line(), input: "debug", expected: log0.LevelDebug},
+	{line: line(), input: "8", err: true},
+	{line: line(), input: "", err: true},
+	{line: line(), input: "trace", err: true},
+}
+
+func TestParseLevel(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range ParseLevelTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("parse level %q %d", tc.input, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			level, err := log0.ParseLevel(tc.input)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected parse error: %v %s", err, linkToExample)
+			}
+
+			if level != tc.expected {
+				t.Errorf("unexpected level, expected: %d, received: %d %s", tc.expected, level, linkToExample)
+			}
+		})
+	}
+}
+
+func TestSeverityWriters(t *testing.T) {
+	var levels []log0.Level
+
+	severity := log0.SeverityWriters(func(level log0.Level, p []byte) (int, error) {
+		levels = append(levels, level)
+		return len(p), nil
+	})
+
+	if severity("foo") != nil {
+		t.Error("unexpected writer of the unknown severity")
+	}
+
+	for _, s := range []string{"3", "warning", "7"} {
+		_, err := severity(s).Write([]byte("bar"))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	if fmt.Sprint(levels) != fmt.Sprint([]log0.Level{log0.LevelError, log0.LevelWarning, log0.LevelDebug}) {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+}
+
+var LevelMethodsTestCases = []struct {
+	line   int
+	level  log0.Level
+	syslog int
+	name   string
+	enum   string
+}{
+	{line: line(), level: log0.LevelEmergency, syslog: 0, name: "emergency", enum: "EMERGENCY"},
+	{line: line(), level: log0.LevelAlert, syslog: 1, name: "alert", enum: "ALERT"},
+	{line: line(), level: log0.LevelCritical, syslog: 2, name: "critical", enum: "CRITICAL"},
+	{line: line(), level: log0.LevelError, syslog: 3, name: "error", enum: "ERROR"},
+	{line: line(), level: log0.LevelWarning, syslog: 4, name: "warning", enum: "WARNING"},
+	{line: line(), level: log0.LevelNotice, syslog: 5, name: "notice", enum: "NOTICE"},
+	{line: line(), level: log0.LevelInfo, syslog: 6, name: "info", enum: "INFO"},
+	{line: line(), level: log0.LevelDebug, syslog: 7, name: "debug", enum: "DEBUG"},
+	{line: line(), level: 0, syslog: -1, name: "Level(0)", enum: "DEFAULT"},
+	{line: line(), level: 9, syslog: -1, name: "Level(9)", enum: "DEFAULT"},
+}
+
+func TestLevelMethods(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range LevelMethodsTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("level methods %s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			if tc.level.Syslog() != tc.syslog {
+				t.Errorf("unexpected syslog number, expected: %d, received: %d %s", tc.syslog, tc.level.Syslog(), linkToExample)
+			}
+
+			if tc.level.String() != tc.name {
+				t.Errorf("unexpected name, expected: %q, received: %q %s", tc.name, tc.level.String(), linkToExample)
+			}
+
+			if tc.level.Enum() != tc.enum {
+				t.Errorf("unexpected enum, expected: %q, received: %q %s", tc.enum, tc.level.Enum(), linkToExample)
+			}
+
+			if !tc.level.Valid() {
+				return
+			}
+
+			p, err := tc.level.MarshalText()
+			if err != nil {
+				t.Fatalf("unexpected marshal error: %s %s", err, linkToExample)
+			}
+
+			var level log0.Level
+
+			err = level.UnmarshalText(p)
+			if err != nil {
+				t.Fatalf("unexpected unmarshal error: %s %s", err, linkToExample)
+			}
+
+			if level != tc.level {
+				t.Errorf("unexpected unmarshaled level, expected: %d, received: %d %s", tc.level, level, linkToExample)
+			}
+		})
+	}
+}
+
+func TestLevelAtLeast(t *testing.T) {
+	if !log0.LevelError.AtLeast(log0.LevelWarning) {
+		t.Error("error is expected to be at least warning")
+	}
+
+	if !log0.LevelWarning.AtLeast(log0.LevelWarning) {
+		t.Error("warning is expected to be at least warning")
+	}
+
+	if log0.LevelInfo.AtLeast(log0.LevelWarning) {
+		t.Error("info is not expected to be at least warning")
+	}
+
+	if log0.Level(0).AtLeast(log0.LevelDebug) {
+		t.Error("undefined level is not expected to be at least debug")
+	}
+}
+
+var SeverityFormatTestCases = []struct {
+	line     int
+	format   uint8
+	expected string
+}{
+	{line: line(), format: log0.SeverityDigit, expected: `{"message":"foo","severity":"4"}`},
+	{line: line(), format: log0.SeverityNumber, expected: `{"message":"foo","severity":4}`},
+	{line: line(), format: log0.SeverityName, expected: `{"message":"foo","severity":"warning"}`},
+	{line: line(), format: log0.SeverityEnum, expected: `{"message":"foo","severity":"WARNING"}`},
+	{line: line(), format: 42, expected: `{"message":"foo","severity":"4"}`},
+}
+
+func TestSeverityFormat(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range SeverityFormatTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("severity format %d %d", tc.format, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			var buf bytes.Buffer
+
+			l := &log0.Log{
+				Output:         &buf,
+				Keys:           [4]encoding.TextMarshaler{log0.String("message")},
+				SeverityFormat: tc.format,
+				Threshold:      log0.LevelWarning,
+			}
+
+			err := l.Warning("foo")
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			ja := jsonassert.New(testprinter{t: t, link: linkToExample})
+			ja.Assertf(buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestThresholdSeverityName(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:    &buf,
+		Keys:      [4]encoding.TextMarshaler{log0.String("message")},
+		Threshold: log0.LevelWarning,
+	}
+
+	l1 := l.Get(log0.StringSeverity("severity", "debug"))
+	defer l1.Put()
+
+	_, err := l1.Write([]byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	l2 := l.Get(log0.StringSeverity("severity", "ERR"))
+	defer l2.Put()
+
+	_, err = l2.Write([]byte("bar"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{"message":"bar","severity":"ERR"}`)
+}
@@ -7,17 +7,22 @@ package log0
 
 import (
 	"bytes"
+	"context"
 	"encoding"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 	"unicode"
 	"unicode/utf8"
 
-	jsoniter "github.com/json-iterator/go"
+	"github.com/danil/log0/encode0"
 )
 
 type Logger interface {
@@ -26,8 +31,29 @@ type Logger interface {
 	// Copy of the original key-values should have a lower priority
 	// than the priority of the newer key-values.
 	Get(...KV) Logger
+	// Ctx returns copy of the logger with an additional key-values
+	// obtained from the context.
+	Ctx(context.Context, ...KV) Logger
 	// Put puts the logger into the sync pool.
 	Put()
+	// Emergency writes the message and key-values with the "0" severity level.
+	Emergency(msg string, kv ...KV) error
+	// Alert writes the message and key-values with the "1" severity level.
+	Alert(msg string, kv ...KV) error
+	// Critical writes the message and key-values with the "2" severity level.
+	Critical(msg string, kv ...KV) error
+	// Error writes the message and key-values with the "3" severity level.
+	Error(msg string, kv ...KV) error
+	// Warning writes the message and key-values with the "4" severity level.
+	Warning(msg string, kv ...KV) error
+	// Notice writes the message and key-values with the "5" severity level.
+	Notice(msg string, kv ...KV) error
+	// Info writes the message and key-values with the "6" severity level.
+	Info(msg string, kv ...KV) error
+	// Debug writes the message and key-values with the "7" severity level.
+	Debug(msg string, kv ...KV) error
+	// Enabled reports whether the logger writes entries of the severity level.
+	Enabled(Level) bool
 }
 
 // KV is a key-value pair.
@@ -43,6 +69,20 @@ type KVS interface {
 	fmt.Stringer
 }
 
+// TextAppender is implemented by any value that can append text of itself
+// to the byte slice without of the intermediate allocations.
+// Log uses AppendText instead of MarshalText if a key implements it.
+type TextAppender interface {
+	AppendText(dst []byte) ([]byte, error)
+}
+
+// JSONAppender is implemented by any value that can append JSON of itself
+// to the byte slice without of the intermediate allocations.
+// Log uses AppendJSON instead of MarshalJSON if a value implements it.
+type JSONAppender interface {
+	AppendJSON(dst []byte) ([]byte, error)
+}
+
 const (
 	Original = iota
 	Excerpt
@@ -56,17 +96,79 @@ const (
 	Blank
 )
 
+const (
+	Alphabetical = iota
+	Insertion
+)
+
+// Timestamp formats.
+const (
+	RFC3339Nano = iota // RFC3339Nano is a "2006-01-02T15:04:05.999999999Z07:00".
+	RFC3339            // RFC3339 is a "2006-01-02T15:04:05Z07:00".
+	Unix               // Unix is a unix seconds with fraction: 1136239445.999999999.
+	UnixMilli          // UnixMilli is a unix milliseconds: 1136239445999.
+	UnixNano           // UnixNano is a unix nanoseconds: 1136239445999999999.
+)
+
+// Caller modes.
+const (
+	CallerFields = iota + 1 // CallerFields is a file path, line number and function name written as a separate fields.
+	CallerObject            // CallerObject is a file path, line number and function name written as a nested object.
+)
+
+// Caller keys.
+const (
+	CallerNested = iota // CallerNested is a key of the nested object.
+	CallerFile          // CallerFile is a key of the file path.
+	CallerLine          // CallerLine is a key of the line number.
+	CallerFunc          // CallerFunc is a key of the function name.
+)
+
+// Clock is a source of the current time.
+type Clock interface {
+	Now() time.Time
+}
+
 // Log is a JSON logger/writer.
 type Log struct {
-	Output   io.Writer                                // Output is a destination for output.
-	Flag     int                                      // Flag is a log properties.
-	KV       []KV                                     // KV is a key-values.
-	Severity func(severity string) (output io.Writer) // Severity function receives severity level and returns a output writer for a severity level.
-	Keys     [4]encoding.TextMarshaler                // Keys: 0 = original message; 1 = message excerpt; 2 = message trail; 3 = file path.
-	Key      uint8                                    // Key is a default/sticky message key: all except 1 = original message; 1 = message excerpt.
-	Trunc    int                                      // Trunc is a maximum length of an excerpt, after which it is truncated.
-	Marks    [3][]byte                                // Marks: 0 = truncate; 1 = empty; 2 = blank.
-	Replace  [][2][]byte                              // Replace ia a pairs of byte slices to replace in the message excerpt.
+	Output         io.Writer                                // Output is a destination for output.
+	Flag           int                                      // Flag is a log properties.
+	KV             []KV                                     // KV is a key-values.
+	Severity       func(severity string) (output io.Writer) // Severity function receives severity level and returns a output writer for a severity level.
+	Keys           [4]encoding.TextMarshaler                // Keys: 0 = original message; 1 = message excerpt; 2 = message trail; 3 = file path.
+	Key            uint8                                    // Key is a default/sticky message key: all except 1 = original message; 1 = message excerpt.
+	Trunc          int                                      // Trunc is a maximum length of an excerpt, after which it is truncated.
+	Marks          [3][]byte                                // Marks: 0 = truncate; 1 = empty; 2 = blank.
+	Replace        [][2][]byte                              // Replace ia a pairs of byte slices to replace in the message excerpt.
+	Order          uint8                                    // Order is a keys order: all except 1 = alphabetical; 1 = insertion order, overridden value replaces the previous one in place.
+	Priority       []encoding.TextMarshaler                 // Priority is a keys which is written first in the given order.
+	SeverityKey    encoding.TextMarshaler                   // SeverityKey is a severity level key of the leveled methods, "severity" if nil; if not nil then also written for the entries of the Get with severity level.
+	Threshold      Level                                    // Threshold is a least severe level which is written, all levels are written if zero.
+	SeverityFormat uint8                                    // SeverityFormat is a format of the severity level of the leveled methods: 0 = syslog number as a string; 1 = syslog number; 2 = name; 3 = cloud logging enum name.
+	Time           encoding.TextMarshaler                   // Time is a timestamp key, timestamp is not written if nil.
+	TimeFormat     uint8                                    // TimeFormat is a timestamp format: all except known = RFC3339Nano; 1 = RFC3339; 2 = unix seconds as a float; 3 = unix milliseconds; 4 = unix nanoseconds.
+	Location       *time.Location                           // Location is a timestamp location, location of the clock time is used if nil.
+	Clock          Clock                                    // Clock is a source of the timestamp, system clock is used if nil.
+	Caller         uint8                                    // Caller is a caller mode: all except known = caller is not written; 1 = separate fields; 2 = nested object.
+	CallerKeys     [4]encoding.TextMarshaler                // CallerKeys: 0 = nested object; 1 = file path; 2 = line number; 3 = function name; "caller", "file", "line" and "function" if nil.
+	CallerSkip     int                                      // CallerSkip is a number of the stack frames to skip above the caller of the Write or the leveled method.
+	CallerPC       uintptr                                  // CallerPC is a program counter of the caller, for example of the slog.Record, caller of the Write or the leveled method is written if zero.
+	CallerTrimFile string                                   // CallerTrimFile is a prefix trimmed from the file path with the following separator, for example a module root directory or a module path of the -trimpath build.
+	CallerTrimFunc string                                   // CallerTrimFunc is a prefix trimmed from the function name with the following separator, for example a module path.
+	Stack          encoding.TextMarshaler                   // Stack is a stack trace key, stack trace is not written if nil.
+	StackLevel     Level                                    // StackLevel is a least severe level which is written with stack trace, error level if zero.
+	StackFormat    uint8                                    // StackFormat is a stack trace format: all except 1 = array of the frames; 1 = single string.
+	Extractors     []Extractor                              // Extractors is a functions which extracts key-values from the context of the Ctx method.
+	Sampler        *Sampler                                 // Sampler samples entries, all entries are written if nil.
+	Dedup          *Dedup                                   // Dedup suppresses identical entries, all entries are written if nil.
+	Rename         func(dst, key []byte) []byte             // Rename appends renamed key of the entry to the dst, keys of the nested objects are not renamed, keys are not renamed if nil.
+
+	level Level  // level is a severity level of the logger obtained from the first key-value of the Get.
+	depth int    // depth is a number of the stack frames between the Write and the caller of the leveled method.
+	sev   kvjt   // sev is a severity key-value of the leveled methods.
+	msg   []byte // msg is a message of the leveled methods.
+
+	summary []KV // summary is a top-level key-values of the entry of the suppressed identical entries.
 }
 
 var logPool = sync.Pool{New: func() interface{} { return new(Log) }}
@@ -78,66 +180,757 @@ var logPool = sync.Pool{New: func() interface{} { return new(Log) }}
 // Then the function from Severity field returns writer for output of the logger.
 // Copy of the original key-values has the priority lower
 // than the priority of the newer key-values.
+// Key-values which follows the namespace key (see StringNamespace)
+// including key-values of the following calls of the Get
+// are nested into the JSON object of the namespace key.
 func (l *Log) Get(kv ...KV) Logger {
 	l0 := logPool.Get().(*Log)
+	l.get(l0, kv)
+
+	if len(kv) > 0 {
+		s, ok := kv[0].(KVS)
+		if ok {
+			l0.leveled(s.String())
+		}
+	}
+
+	return l0
+}
+
+func (l *Log) get(l0 *Log, kv []KV) {
 	l0.Output = l.Output
 	l0.Flag = l.Flag
-	l0.KV = append(l0.KV[:0], append(l.KV, kv...)...)
+	l0.KV = append(append(l0.KV[:0], l.KV...), kv...)
 	l0.Severity = l.Severity
 	l0.Keys = l.Keys
 	l0.Key = l.Key
 	l0.Trunc = l.Trunc
 	l0.Marks = l.Marks
 	l0.Replace = append(l0.Replace[:0], l.Replace...)
+	l0.Order = l.Order
+	l0.Priority = append(l0.Priority[:0], l.Priority...)
+	l0.SeverityKey = l.SeverityKey
+	l0.Threshold = l.Threshold
+	l0.SeverityFormat = l.SeverityFormat
+	l0.Time = l.Time
+	l0.TimeFormat = l.TimeFormat
+	l0.Location = l.Location
+	l0.Clock = l.Clock
+	l0.Caller = l.Caller
+	l0.CallerKeys = l.CallerKeys
+	l0.CallerSkip = l.CallerSkip
+	l0.CallerPC = l.CallerPC
+	l0.CallerTrimFile = l.CallerTrimFile
+	l0.CallerTrimFunc = l.CallerTrimFunc
+	l0.Stack = l.Stack
+	l0.StackLevel = l.StackLevel
+	l0.StackFormat = l.StackFormat
+	l0.Extractors = append(l0.Extractors[:0], l.Extractors...)
+	l0.Sampler = l.Sampler
+	l0.Dedup = l.Dedup
+	l0.Rename = l.Rename
+	l0.depth = 0
+	l0.level = l.level
+	l0.sev = kvjt{}
+	l0.summary = nil
+}
 
-	if l0.Severity != nil && len(kv) > 0 {
-		s, ok := kv[0].(KVS)
-		if ok {
-			out := l0.Severity(s.String())
-			if out != nil {
-				l0.Output = out
-			}
-		}
+// detach returns copy of the logger which does not share
+// the key-values and the other buffers with the pooled logger.
+func (l *Log) detach() *Log {
+	l0 := *l
+	l0.KV = append([]KV(nil), l.KV...)
+	l0.Replace = append([][2][]byte(nil), l.Replace...)
+	l0.Priority = append([]encoding.TextMarshaler(nil), l.Priority...)
+	l0.Extractors = nil
+	l0.Sampler = nil
+	l0.Dedup = nil
+	l0.msg = nil
+	return &l0
+}
+
+// leveled sets the severity level of the logger
+// and the output of the severity level.
+func (l *Log) leveled(severity string) {
+	level, err := ParseLevel(severity)
+	if err == nil {
+		l.level = level
 	}
 
-	return l0
+	if l.Severity != nil {
+		out := l.Severity(severity)
+		if out != nil {
+			l.Output = out
+		}
+	}
 }
 
 // Put puts a log into sync pool.
 func (l *Log) Put() { logPool.Put(l) }
 
-// Write implements io.Writer. Do nothing if log does not have output.
+var severityKey encoding.TextMarshaler = String("severity")
+
+func (l *Log) Emergency(msg string, kv ...KV) error { return l.write(LevelEmergency, msg, kv) }
+func (l *Log) Alert(msg string, kv ...KV) error     { return l.write(LevelAlert, msg, kv) }
+func (l *Log) Critical(msg string, kv ...KV) error  { return l.write(LevelCritical, msg, kv) }
+func (l *Log) Error(msg string, kv ...KV) error     { return l.write(LevelError, msg, kv) }
+func (l *Log) Warning(msg string, kv ...KV) error   { return l.write(LevelWarning, msg, kv) }
+func (l *Log) Notice(msg string, kv ...KV) error    { return l.write(LevelNotice, msg, kv) }
+func (l *Log) Info(msg string, kv ...KV) error      { return l.write(LevelInfo, msg, kv) }
+func (l *Log) Debug(msg string, kv ...KV) error     { return l.write(LevelDebug, msg, kv) }
+
+// Enabled reports whether the logger writes entries of the severity level.
+// Entries of the undefined level are always written.
+func (l *Log) Enabled(level Level) bool {
+	return l.Threshold == 0 || level <= l.Threshold
+}
+
+// write writes the message with the severity level and key-values
+// as if it is written by the copy of the logger obtained
+// by the Get with the key-values. Severity key-value is written
+// outside of the namespace and overrides the other severity key-values.
+func (l *Log) write(severity Level, msg string, kv []KV) error {
+	if !l.Enabled(severity) {
+		return nil
+	}
+
+	l0 := logPool.Get().(*Log)
+	defer l0.Put()
+
+	k := l.SeverityKey
+	if k == nil {
+		k = severityKey
+	}
+
+	l.get(l0, kv)
+
+	if l0.Severity != nil {
+		out := l0.Severity(digits[severity].String())
+		if out != nil {
+			l0.Output = out
+		}
+	}
+
+	l0.sev = kvjt{K: k, V: severity.Severity(l.SeverityFormat)}
+	l0.level = severity
+	l0.depth = 2
+
+	l0.msg = append(l0.msg[:0], msg...)
+
+	_, err := l0.Write(l0.msg)
+
+	return err
+}
+
+// Write implements io.Writer. Do nothing if log does not have output
+// or the severity level of the log is below the threshold.
 func (l *Log) Write(src []byte) (int, error) {
-	if l.Output == nil {
+	if l.Output == nil || !l.Enabled(l.level) {
 		return 0, nil
 	}
-	j, err := l.json(src)
+
+	var n uint64
+	if l.Sampler != nil {
+		var ok bool
+		ok, n = l.Sampler.sample(l.level, src)
+		if !ok {
+			return 0, nil
+		}
+	}
+
+	enc := encoderPool.Get().(*encoder)
+	defer encoderPool.Put(enc)
+
+	enc.dr.N = n
+
+	if l.Caller == CallerFields || l.Caller == CallerObject {
+		if l.CallerPC != 0 {
+			enc.pc[0] = l.CallerPC
+		} else {
+			enc.frame(2 + l.depth + l.CallerSkip)
+		}
+	}
+
+	enc.st.V.PC = nil
+	if l.stacked() {
+		n := runtime.Callers(2+l.depth+l.CallerSkip, enc.pcs[:])
+		enc.st = stack{K: l.Stack, V: stackV{PC: enc.pcs[:n], F: l.StackFormat}}
+	}
+
+	if l.Dedup != nil {
+		l0 := *l
+		l0.Time = nil
+
+		err := l0.json(enc, src)
+		if err != nil {
+			return 0, err
+		}
+
+		ok, err := l.Dedup.seen(l, enc, src, enc.p)
+		if !ok || err != nil {
+			return 0, err
+		}
+	}
+
+	err := l.json(enc, src)
 	if err != nil {
 		return 0, err
 	}
-	return l.Output.Write(j)
+
+	return l.Output.Write(enc.p)
 }
 
 var asciiSpace = [256]uint8{'\t': 1, '\n': 1, '\v': 1, '\f': 1, '\r': 1, ' ': 1}
 
-var (
-	mapPool     = sync.Pool{New: func() interface{} { m := make(map[string]json.Marshaler); return &m }}
-	excerptPool = sync.Pool{New: func() interface{} { return new([]byte) }}
-)
+// encoder is a reusable state of the log entry encoding.
+type encoder struct {
+	p       []byte                       // p is a JSON output.
+	keys    []byte                       // keys is a concatenated texts of the keys.
+	entries []entry                      // entries is a key-values in order of the assignment.
+	excerpt []byte                       // excerpt is a message excerpt.
+	order   []int                        // order is an indexes of the entries in order of the output.
+	ts      timestamp                    // ts is a timestamp key-value.
+	pc      [1]uintptr                   // pc is a program counter of the caller.
+	callers [4]caller                    // callers is a caller key-values.
+	nested  [3]KV                        // nested is a caller key-values of the nested object.
+	pcs     [stackDepth]uintptr          // pcs is a program counters of the stack trace.
+	st      stack                        // st is a stack trace key-value.
+	dr      dropped                      // dr is a number of the dropped entries key-value.
+	sv      kvjt                         // sv is a severity level key-value of the entries of the Get.
+	rename  func(dst, key []byte) []byte // rename appends renamed key to the dst.
+	scratch []byte                       // scratch is a copy of the renamed key.
+}
+
+// entry is a key-value assignment.
+type entry struct {
+	k  [2]int // k is a begin and end offsets of the key text in the keys buffer.
+	v  KV     // v is a key-value pair or nil if the value is a message bytes or a namespace.
+	p  []byte // p is a message bytes.
+	ns []KV   // ns is a key-values of the namespace.
+}
+
+var encoderPool = sync.Pool{New: func() interface{} { return new(encoder) }}
+
+func (enc *encoder) reset() {
+	enc.p = enc.p[:0]
+	enc.keys = enc.keys[:0]
+	enc.entries = enc.entries[:0]
+	enc.excerpt = enc.excerpt[:0]
+	enc.order = enc.order[:0]
+	enc.rename = nil
+}
+
+// timestamp assigns timestamp if the timestamp key is not nil.
+func (l Log) timestamp(enc *encoder) error {
+	if l.Time == nil {
+		return nil
+	}
+
+	k, err := enc.key(l.Time)
+	if err != nil {
+		return err
+	}
+
+	var t time.Time
+	if l.Clock == nil {
+		t = time.Now()
+	} else {
+		t = l.Clock.Now()
+	}
+
+	if l.Location != nil {
+		t = t.In(l.Location)
+	}
+
+	enc.ts = timestamp{K: l.Time, V: t, F: l.TimeFormat}
+	enc.entries = append(enc.entries, entry{k: k, v: &enc.ts})
+
+	return nil
+}
+
+// timestamp is a timestamp key-value pair.
+type timestamp struct {
+	K encoding.TextMarshaler
+	V time.Time
+	F uint8 // F is a timestamp format.
+}
+
+func (kv *timestamp) MarshalText() ([]byte, error) { return kv.K.MarshalText() }
+
+func (kv *timestamp) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+
+func (kv *timestamp) MarshalJSON() ([]byte, error) { return kv.AppendJSON(nil) }
+
+func (kv *timestamp) AppendJSON(dst []byte) ([]byte, error) {
+	switch kv.F {
+	case RFC3339:
+		if y := kv.V.Year(); y < 0 || y > 9999 {
+			return Time(kv.V).AppendJSON(dst)
+		}
+		dst = append(dst, '"')
+		dst = kv.V.AppendFormat(dst, time.RFC3339)
+		return append(dst, '"'), nil
+
+	case Unix:
+		sec, nsec := kv.V.Unix(), int64(kv.V.Nanosecond())
+		if sec < 0 && nsec != 0 {
+			dst = append(dst, '-')
+			sec, nsec = -sec-1, 1e9-nsec
+		}
+		dst = strconv.AppendInt(dst, sec, 10)
+		if nsec == 0 {
+			return dst, nil
+		}
+		var frac [10]byte
+		frac[0] = '.'
+		for i := 9; i > 0; i-- {
+			frac[i] = byte(nsec%10) + '0'
+			nsec /= 10
+		}
+		n := len(frac)
+		for frac[n-1] == '0' {
+			n--
+		}
+		return append(dst, frac[:n]...), nil
+
+	case UnixMilli:
+		return strconv.AppendInt(dst, kv.V.Unix()*1e3+int64(kv.V.Nanosecond())/1e6, 10), nil
+
+	case UnixNano:
+		return strconv.AppendInt(dst, kv.V.UnixNano(), 10), nil
+	}
+
+	return Time(kv.V).AppendJSON(dst)
+}
+
+// stacked reports whether the entry is written with stack trace.
+func (l *Log) stacked() bool {
+	if l.Stack == nil {
+		return false
+	}
+	if l.StackLevel == 0 {
+		return l.level.AtLeast(LevelError)
+	}
+	return l.level.AtLeast(l.StackLevel)
+}
+
+// frame captures program counter of the caller skipping the number of the stack frames
+// above the caller of the frame.
+func (enc *encoder) frame(skip int) {
+	enc.pc[0] = 0
+	runtime.Callers(skip+1, enc.pc[:])
+}
+
+// frames is a cache of the caller frames by program counters.
+var frames = struct {
+	sync.RWMutex
+	m map[uintptr]runtime.Frame
+}{m: make(map[uintptr]runtime.Frame)}
+
+// callerFrame returns frame of the program counter.
+func callerFrame(pc uintptr) runtime.Frame {
+	frames.RLock()
+	f, ok := frames.m[pc]
+	frames.RUnlock()
+	if ok {
+		return f
+	}
+
+	f, _ = runtime.CallersFrames([]uintptr{pc}).Next()
+
+	frames.Lock()
+	frames.m[pc] = f
+	frames.Unlock()
+
+	return f
+}
+
+var callerKeys = [4]encoding.TextMarshaler{
+	String("caller"),
+	String("file"),
+	String("line"),
+	String("function"),
+}
+
+// trimPrefix returns s without the prefix and without the separator
+// which follows the prefix. If the prefix does not end by the separator
+// and is not followed by the separator then s is returned unchanged.
+func trimPrefix(s, prefix, separators string) string {
+	if prefix == "" || !strings.HasPrefix(s, prefix) {
+		return s
+	}
+	if strings.IndexByte(separators, prefix[len(prefix)-1]) != -1 {
+		return s[len(prefix):]
+	}
+	if len(s) > len(prefix) && strings.IndexByte(separators, s[len(prefix)]) != -1 {
+		return s[len(prefix)+1:]
+	}
+	return s
+}
+
+// caller assigns caller key-values captured by the frame.
+func (l Log) caller(enc *encoder) error {
+	if l.Caller != CallerFields && l.Caller != CallerObject || enc.pc[0] == 0 {
+		return nil
+	}
+
+	f := callerFrame(enc.pc[0])
+
+	for i := range enc.callers {
+		k := l.CallerKeys[i]
+		if k == nil {
+			k = callerKeys[i]
+		}
+		enc.callers[i] = caller{K: k, I: uint8(i)}
+	}
+
+	enc.callers[CallerFile].S = trimPrefix(f.File, l.CallerTrimFile, `/\`)
+	enc.callers[CallerLine].N = f.Line
+	enc.callers[CallerFunc].S = trimPrefix(f.Function, l.CallerTrimFunc, "/.")
+
+	if l.Caller == CallerObject {
+		enc.nested = [3]KV{&enc.callers[CallerFile], &enc.callers[CallerLine], &enc.callers[CallerFunc]}
+		enc.callers[CallerNested].O = enc.nested[:]
+		return enc.kv(&enc.callers[CallerNested])
+	}
+
+	for i := CallerFile; i <= CallerFunc; i++ {
+		err := enc.kv(&enc.callers[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// kv assigns the key-value.
+func (enc *encoder) kv(kv KV) error {
+	k, err := enc.key(kv)
+	if err != nil {
+		return err
+	}
+
+	enc.entries = append(enc.entries, entry{k: k, v: kv})
+
+	return nil
+}
+
+// caller is a caller key-value pair.
+type caller struct {
+	K encoding.TextMarshaler
+	I uint8  // I is an index of the caller key.
+	S string // S is a file path or a function name.
+	N int    // N is a line number.
+	O []KV   // O is a file path, line number and function name of the nested object.
+}
+
+func (kv *caller) MarshalText() ([]byte, error) { return kv.K.MarshalText() }
+
+func (kv *caller) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+
+func (kv *caller) MarshalJSON() ([]byte, error) { return kv.AppendJSON(nil) }
+
+func (kv *caller) AppendJSON(dst []byte) ([]byte, error) {
+	switch kv.I {
+	case CallerNested:
+		return appendObject(dst, kv.O)
+
+	case CallerLine:
+		return strconv.AppendInt(dst, int64(kv.N), 10), nil
+	}
+
+	dst = append(dst, '"')
+	dst = encode0.AppendString(dst, kv.S)
+	return append(dst, '"'), nil
+}
 
-func (l Log) json(src []byte) ([]byte, error) {
-	tmpKV := *mapPool.Get().(*map[string]json.Marshaler)
-	for k := range tmpKV {
-		delete(tmpKV, k)
+// key appends text of the key to the keys buffer and returns offsets of the key.
+// Nil key is the empty key.
+func (enc *encoder) key(k encoding.TextMarshaler) ([2]int, error) {
+	begin := len(enc.keys)
+	if k == nil {
+		return [2]int{begin, begin}, nil
 	}
-	defer mapPool.Put(&tmpKV)
 
-	for _, kv := range l.KV {
-		p, err := kv.MarshalText()
+	var err error
+
+	enc.keys, err = appendText(enc.keys, k)
+	if err != nil {
+		return [2]int{}, err
+	}
+
+	if enc.rename != nil {
+		enc.scratch = append(enc.scratch[:0], enc.keys[begin:]...)
+		enc.keys = enc.rename(enc.keys[:begin], enc.scratch)
+	}
+
+	return [2]int{begin, len(enc.keys)}, nil
+}
+
+// has reports whether the key was assigned.
+func (enc *encoder) has(k [2]int) bool {
+	for _, e := range enc.entries {
+		if bytes.Equal(enc.keys[e.k[0]:e.k[1]], enc.keys[k[0]:k[1]]) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal reports whether the keys of the i-th and j-th assignments are equal.
+func (enc *encoder) equal(i, j int) bool {
+	ki, kj := enc.entries[i].k, enc.entries[j].k
+	return bytes.Equal(enc.keys[ki[0]:ki[1]], enc.keys[kj[0]:kj[1]])
+}
+
+// sort orders the assignments for the output.
+// Only the last assignment of the each key is kept.
+// In insertion order the last assignment takes place of the first one.
+func (enc *encoder) sort(order uint8) {
+	for i := range enc.entries {
+		if order == Insertion {
+			first := true
+			for j := 0; j < i; j++ {
+				if enc.equal(i, j) {
+					first = false
+					break
+				}
+			}
+			if !first {
+				continue
+			}
+		}
+
+		last := i
+		for j := i + 1; j < len(enc.entries); j++ {
+			if enc.equal(i, j) {
+				last = j
+			}
+		}
+
+		if order != Insertion && last != i {
+			continue
+		}
+
+		enc.order = append(enc.order, last)
+	}
+
+	if order == Insertion {
+		return
+	}
+
+	// Insertion sort is stable and does not allocates.
+	for i := 1; i < len(enc.order); i++ {
+		for j := i; j > 0; j-- {
+			a, b := enc.entries[enc.order[j-1]].k, enc.entries[enc.order[j]].k
+			if bytes.Compare(enc.keys[a[0]:a[1]], enc.keys[b[0]:b[1]]) <= 0 {
+				break
+			}
+			enc.order[j-1], enc.order[j] = enc.order[j], enc.order[j-1]
+		}
+	}
+}
+
+// kvs assigns the key-values. Key-values which follows the namespace
+// are assigned to the namespace.
+func (enc *encoder) kvs(kvs []KV) error {
+	for i, kv := range kvs {
+		k, err := enc.key(kv)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := kv.(kvns); ok {
+			enc.entries = append(enc.entries, entry{k: k, ns: kvs[i+1:]})
+			return nil
+		}
+
+		enc.entries = append(enc.entries, entry{k: k, v: kv})
+
+		if p, ok := kv.(kvjt); ok {
+			if c, ok := p.V.(errorChainV); ok {
+				err = c.kvs(enc)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (enc *encoder) bytes(k [2]int, p []byte) {
+	enc.entries = append(enc.entries, entry{k: k, p: p})
+}
+
+// encode appends JSON object of the all not overridden assignments
+// to the output. Keys from the priority list written first.
+func (enc *encoder) encode(order uint8, priority []encoding.TextMarshaler) error {
+	enc.sort(order)
+
+	enc.p = append(enc.p, '{')
+
+	first := true
+
+	for _, k := range priority {
+		offsets, err := enc.key(k)
+		if err != nil {
+			return err
+		}
+
+		key := enc.keys[offsets[0]:offsets[1]]
+
+		for i, j := range enc.order {
+			if j == -1 {
+				continue
+			}
+
+			e := enc.entries[j]
+
+			if !bytes.Equal(enc.keys[e.k[0]:e.k[1]], key) {
+				continue
+			}
+
+			err = enc.entry(e, first)
+			if err != nil {
+				return err
+			}
+
+			first = false
+			enc.order[i] = -1
+
+			break
+		}
+	}
+
+	for _, j := range enc.order {
+		if j == -1 {
+			continue
+		}
+
+		err := enc.entry(enc.entries[j], first)
+		if err != nil {
+			return err
+		}
+
+		first = false
+	}
+
+	enc.p = append(enc.p, '}')
+
+	return nil
+}
+
+// entry appends JSON key-value of the assignment to the output.
+func (enc *encoder) entry(e entry, first bool) error {
+	if !first {
+		enc.p = append(enc.p, ',')
+	}
+
+	enc.p = append(enc.p, '"')
+	enc.p = encode0.AppendBytes(enc.p, enc.keys[e.k[0]:e.k[1]])
+	enc.p = append(enc.p, '"', ':')
+
+	var err error
+
+	if e.ns != nil {
+		enc.p, err = appendObject(enc.p, e.ns)
+		return err
+	}
+
+	if e.v == nil {
+		enc.p = appendQuoted(enc.p, e.p)
+		return nil
+	}
+
+	enc.p, err = appendJSON(enc.p, e.v)
+
+	return err
+}
+
+// appendObject appends JSON object of the key-values to the dst.
+// Keys of the object are written in insertion order.
+func appendObject(dst []byte, kvs []KV) ([]byte, error) {
+	enc := encoderPool.Get().(*encoder)
+	defer encoderPool.Put(enc)
+
+	enc.reset()
+
+	err := enc.kvs(kvs)
+	if err != nil {
+		return dst, err
+	}
+
+	err = enc.encode(Insertion, nil)
+	if err != nil {
+		return dst, err
+	}
+
+	return append(dst, enc.p...), nil
+}
+
+func (l Log) json(enc *encoder, src []byte) error {
+	enc.reset()
+	enc.rename = l.Rename
+
+	err := l.timestamp(enc)
+	if err != nil {
+		return err
+	}
+
+	err = l.caller(enc)
+	if err != nil {
+		return err
+	}
+
+	if enc.dr.N != 0 && l.Sampler.Key != nil {
+		enc.dr.K = l.Sampler.Key
+		err = enc.kv(&enc.dr)
 		if err != nil {
-			return nil, err
+			return err
+		}
+	}
+
+	if enc.st.V.PC != nil {
+		err = enc.kv(&enc.st)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = enc.kvs(l.KV)
+	if err != nil {
+		return err
+	}
+
+	if l.sev.K != nil {
+		k, err := enc.key(l.sev.K)
+		if err != nil {
+			return err
+		}
+
+		enc.sv = l.sev
+		enc.entries = append(enc.entries, entry{k: k, v: &enc.sv})
+
+	} else if l.SeverityKey != nil && l.level.Valid() {
+		k, err := enc.key(l.SeverityKey)
+		if err != nil {
+			return err
+		}
+
+		if !enc.has(k) {
+			enc.sv = kvjt{K: l.SeverityKey, V: l.level.Severity(l.SeverityFormat)}
+			enc.entries = append(enc.entries, entry{k: k, v: &enc.sv})
+		}
+	}
+
+	for _, kv := range l.summary {
+		err = enc.kv(kv)
+		if err != nil {
+			return err
 		}
-		tmpKV[string(p)] = kv
 	}
 
 	var tail, file int
@@ -156,37 +949,19 @@ func (l Log) json(src []byte) ([]byte, error) {
 		}
 	}
 
-	var originalKey string
-
-	if l.Keys[Original] == nil {
-		originalKey = ""
-	} else {
-		p, err := l.Keys[Original].MarshalText()
-		if err != nil {
-			return nil, err
-		}
-		originalKey = string(p)
+	originalKey, err := enc.key(l.Keys[Original])
+	if err != nil {
+		return err
 	}
 
-	var excerptKey string
-
-	if l.Keys[Excerpt] == nil {
-		excerptKey = ""
-	} else {
-		p, err := l.Keys[Excerpt].MarshalText()
-		if err != nil {
-			return nil, err
-		}
-		excerptKey = string(p)
+	excerptKey, err := enc.key(l.Keys[Excerpt])
+	if err != nil {
+		return err
 	}
 
-	excerpt := *excerptPool.Get().(*[]byte)
-	excerpt = excerpt[:0]
-	defer excerptPool.Put(&excerpt)
-
-	if tmpKV[excerptKey] == nil {
-		if src != nil && tail == len(src) && tmpKV[originalKey] == nil {
-			excerpt = append(excerpt, l.Marks[Empty]...)
+	if !enc.has(excerptKey) {
+		if src != nil && tail == len(src) && !enc.has(originalKey) {
+			enc.excerpt = append(enc.excerpt, l.Marks[Empty]...)
 
 		} else if tail != len(src) {
 			n := len(src) + len(l.Marks[Trunc])
@@ -196,74 +971,62 @@ func (l Log) json(src []byte) ([]byte, error) {
 				}
 			}
 
-			excerpt = append(excerpt, make([]byte, n)...)
-			n, err := l.Truncate(excerpt, src[tail:])
+			enc.excerpt = append(enc.excerpt, make([]byte, n)...)
+			n, err := l.Truncate(enc.excerpt, src[tail:])
 			if err != nil {
-				return nil, err
+				return err
 			}
 
-			excerpt = excerpt[:n]
+			enc.excerpt = enc.excerpt[:n]
 		}
 	}
 
-	var trailKey string
-
-	if l.Keys[Trail] == nil {
-		trailKey = ""
-	} else {
-		p, err := l.Keys[Trail].MarshalText()
-		if err != nil {
-			return nil, err
-		}
-		trailKey = string(p)
+	trailKey, err := enc.key(l.Keys[Trail])
+	if err != nil {
+		return err
 	}
 
-	if bytes.Equal(src, excerpt) && src != nil {
+	if bytes.Equal(src, enc.excerpt) && src != nil {
 		if l.Key == Excerpt {
-			tmpKV[excerptKey] = Bytes(src)
+			enc.bytes(excerptKey, src)
 
 		} else {
-			if tmpKV[originalKey] == nil {
-				tmpKV[originalKey] = Bytes(src)
+			if !enc.has(originalKey) {
+				enc.bytes(originalKey, src)
 			} else if len(src) != 0 {
-				tmpKV[trailKey] = Bytes(src)
+				enc.bytes(trailKey, src)
 			}
 		}
 
-	} else if !bytes.Equal(src, excerpt) {
-		if tmpKV[originalKey] == nil {
-			tmpKV[originalKey] = Bytes(src)
-		} else if tmpKV[originalKey] != nil && len(src) != 0 {
-			tmpKV[trailKey] = Bytes(src)
+	} else if !bytes.Equal(src, enc.excerpt) {
+		if !enc.has(originalKey) {
+			enc.bytes(originalKey, src)
+		} else if len(src) != 0 {
+			enc.bytes(trailKey, src)
 		}
 
-		if tmpKV[excerptKey] == nil && len(excerpt) != 0 {
-			tmpKV[excerptKey] = Bytes(excerpt)
+		if !enc.has(excerptKey) && len(enc.excerpt) != 0 {
+			enc.bytes(excerptKey, enc.excerpt)
 		}
 	}
 
-	var fileKey string
-
-	if l.Keys[File] == nil {
-		fileKey = ""
-	} else {
-		p, err := l.Keys[File].MarshalText()
-		if err != nil {
-			return nil, err
-		}
-		fileKey = string(p)
+	fileKey, err := enc.key(l.Keys[File])
+	if err != nil {
+		return err
 	}
 
 	if file != 0 {
-		tmpKV[fileKey] = Bytes(src[:file])
+		enc.bytes(fileKey, src[:file])
 	}
 
-	p, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(tmpKV)
+	err = enc.encode(l.Order, l.Priority)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return append(p, '\n'), nil
+	enc.p = append(enc.p, '\n')
+
+	return nil
 }
 
 // lastIndexFunc is the same as bytes.LastIndexFunc except that if
@@ -363,7 +1126,8 @@ replc:
 
 			offset += idx
 
-			copy(dst, append(dst[:offset], append(r[1], dst[offset+len(r[0]):]...)...))
+			copy(dst[offset+len(r[1]):], dst[offset+len(r[0]):n])
+			copy(dst[offset:], r[1])
 
 			offset += len(r[1])
 			n += len(r[1]) - len(r[0])
@@ -389,17 +1153,68 @@ func GELF() *Log {
 		// <https://github.com/graylog-labs/gelf-rb/issues/41#issuecomment-198266505>.
 		KV: []KV{
 			Strings("version", "1.1"),
-			StringFunc("timestamp", func() KV { return Int64(time.Now().Unix()) }),
+			Strings("host", gelfHost()),
 		},
-		Trunc: 120,
+		Time:       String("timestamp"),
+		TimeFormat: Unix,
+		Trunc:      120,
 		Keys: [4]encoding.TextMarshaler{
 			String("full_message"),
 			String("short_message"),
 			String("_trail"),
 			String("_file"),
 		},
-		Key:     Excerpt,
-		Marks:   [3][]byte{[]byte("…"), []byte("_EMPTY_"), []byte("_BLANK_")},
-		Replace: [][2][]byte{[2][]byte{[]byte("\n"), []byte(" ")}},
+		Key:            Excerpt,
+		Marks:          [3][]byte{[]byte("…"), []byte("_EMPTY_"), []byte("_BLANK_")},
+		Replace:        [][2][]byte{[2][]byte{[]byte("\n"), []byte(" ")}},
+		SeverityKey:    String("level"),
+		SeverityFormat: SeverityNumber,
+		Rename:         GELFKey,
+	}
+}
+
+// gelfHost returns a host name of the GELF entries.
+func gelfHost() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	return host
+}
+
+// GELFKey appends the key to the dst renamed in accordance with GELF 1.1:
+// the keys of the spec are appended verbatim,
+// the additional keys are prefixed with underscore,
+// characters which are not letters, digits, underscores, dots or dashes
+// are replaced by underscore and the reserved "_id" key is renamed to "__id".
+func GELFKey(dst, key []byte) []byte {
+	switch string(key) {
+	case "version", "host", "short_message", "full_message", "timestamp", "level":
+		return append(dst, key...)
+	case "_id", "id":
+		return append(dst, "__id"...)
+	}
+
+	if len(key) == 0 || key[0] != '_' {
+		dst = append(dst, '_')
+	}
+
+	for i := 0; i < len(key); {
+		c := key[i]
+		if c < utf8.RuneSelf {
+			if c == '_' || c == '.' || c == '-' ||
+				'0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+				dst = append(dst, c)
+			} else {
+				dst = append(dst, '_')
+			}
+			i++
+			continue
+		}
+		_, n := utf8.DecodeRune(key[i:])
+		dst = append(dst, '_')
+		i += n
 	}
+
+	return dst
 }
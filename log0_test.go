@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -833,6 +835,64 @@ var WriteTestCases = []struct {
 			"foo":"xyz"
 		}`,
 	},
+	{
+		name: "nested object",
+		line: line(),
+		log:  dummy(),
+		kv: []log0.KV{
+			log0.StringObject("http",
+				log0.Strings("method", "GET"),
+				log0.StringInt("status", 200),
+			),
+		},
+		expected: `{
+			"http":{"method":"GET","status":200}
+		}`,
+	},
+	{
+		name: "nested object with escaped key and value and overridden key",
+		line: line(),
+		log:  dummy(),
+		kv: []log0.KV{
+			log0.StringObject("foo",
+				log0.Strings("bar\n", "baz\"xyz\""),
+				log0.Strings("abc", "def"),
+				log0.Strings("abc", "ghi"),
+			),
+		},
+		expected: `{
+			"foo":{"bar\n":"baz\"xyz\"","abc":"ghi"}
+		}`,
+	},
+	{
+		name: "namespace",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.Strings("foo", "bar"), log0.StringNamespace("http")},
+			Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		},
+		input: []byte("Hello, World!"),
+		kv:    []log0.KV{log0.Strings("method", "GET"), log0.StringInt("status", 200)},
+		expected: `{
+			"foo":"bar",
+			"message":"Hello, World!",
+			"http":{"method":"GET","status":200}
+		}`,
+		benchmark: true,
+	},
+	{
+		name: "nested namespaces",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.StringNamespace("foo")},
+		},
+		kv: []log0.KV{log0.Strings("bar", "baz"), log0.StringNamespace("xyz"), log0.StringInt("abc", 1)},
+		expected: `{
+			"foo":{"bar":"baz","xyz":{"abc":1}}
+		}`,
+	},
 }
 
 func TestWrite(t *testing.T) {
@@ -901,6 +961,7 @@ var FprintWriteTestCases = []struct {
 			l1.Output = &bytes.Buffer{}
 			l := l1.Get(
 				log0.Strings("version", "1.1"),
+				log0.Strings("host", "example.tld"),
 				log0.StringFunc("timestamp", func() log0.KV {
 					t := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
 					return log0.Int64(t.Unix())
@@ -911,6 +972,7 @@ var FprintWriteTestCases = []struct {
 		input: "Hello,\nGELF!",
 		expected: `{
 			"version":"1.1",
+			"host":"example.tld",
 			"short_message":"Hello, GELF!",
 			"full_message":"Hello,\nGELF!",
 			"timestamp":1602785340
@@ -1466,6 +1528,73 @@ var FprintWriteTestCases = []struct {
 			"_file":"path/to/file7:89"
 		}`,
 	},
+	{
+		name: "GELF additional fields and level",
+		line: line(),
+		log: func() log0.Logger {
+			l1 := log0.GELF()
+			l1.Output = &bytes.Buffer{}
+			l1.Time = nil
+			l := l1.Get(
+				log0.StringSeverity("severity", "warning"),
+				log0.Strings("host", "example.tld"),
+				log0.Strings("id", "42"),
+				log0.Strings("_id", "43"),
+				log0.Strings("user name", "Alice"),
+				log0.Strings("_request-id.v1", "abc"),
+				log0.StringObject("http", log0.Strings("status code", "200")),
+			)
+			return l
+		}(),
+		input: "Hello, GELF!",
+		expected: `{
+			"version":"1.1",
+			"short_message":"Hello, GELF!",
+			"host":"example.tld",
+			"level":4,
+			"_severity":"warning",
+			"__id":"43",
+			"_user_name":"Alice",
+			"_request-id.v1":"abc",
+			"_http":{"status code":"200"}
+		}`,
+	},
+}
+
+var GELFKeyTestCases = []struct {
+	key      string
+	expected string
+}{
+	{key: "version", expected: "version"},
+	{key: "host", expected: "host"},
+	{key: "short_message", expected: "short_message"},
+	{key: "full_message", expected: "full_message"},
+	{key: "timestamp", expected: "timestamp"},
+	{key: "level", expected: "level"},
+	{key: "foo", expected: "_foo"},
+	{key: "_foo", expected: "_foo"},
+	{key: "foo.bar-baz_1", expected: "_foo.bar-baz_1"},
+	{key: "foo bar", expected: "_foo_bar"},
+	{key: "foo/bar:baz", expected: "_foo_bar_baz"},
+	{key: "привет", expected: "_______"},
+	{key: "", expected: "_"},
+	{key: "id", expected: "__id"},
+	{key: "_id", expected: "__id"},
+	{key: "__id", expected: "__id"},
+}
+
+func TestGELFKey(t *testing.T) {
+	for _, tc := range GELFKeyTestCases {
+		tc := tc
+		t.Run(tc.key, func(t *testing.T) {
+			t.Parallel()
+
+			key := log0.GELFKey([]byte("x"), []byte(tc.key))
+			if string(key) != "x"+tc.expected {
+				t.Errorf("unexpected key, expected: %q, recieved: %q", "x"+tc.expected, key)
+			}
+		})
+	}
 }
 
 func TestFprintWrite(t *testing.T) {
@@ -1512,6 +1641,22 @@ func BenchmarkLog0(b *testing.B) {
 		})
 	}
 
+	for _, tc := range OrderTestCases {
+		if !tc.benchmark {
+			continue
+		}
+		b.Run(fmt.Sprintf("io.Writer ordered %d", tc.line), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				l := tc.log.Get(tc.kv...)
+				_, err := l.Write(tc.input)
+				if err != nil {
+					fmt.Println(err)
+				}
+				l.Put()
+			}
+		})
+	}
+
 	for _, tc := range FprintWriteTestCases {
 		if !tc.benchmark {
 			continue
@@ -1553,6 +1698,27 @@ func TestLogWriteTrailingNewLine(t *testing.T) {
 	}
 }
 
+type appender struct{}
+
+func (appender) MarshalText() ([]byte, error)          { return []byte("marshal"), nil }
+func (appender) MarshalJSON() ([]byte, error)          { return []byte(`"marshal"`), nil }
+func (appender) AppendText(dst []byte) ([]byte, error) { return append(dst, "append"...), nil }
+func (appender) AppendJSON(dst []byte) ([]byte, error) { return append(dst, `"append"`...), nil }
+
+func TestLogWriteAppender(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{Output: &buf, KV: []log0.KV{appender{}}}
+
+	_, err := l.Write(nil)
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{"append":"append"}`)
+}
+
 var TruncateTestCases = []struct {
 	name      string
 	line      int
@@ -1930,3 +2096,683 @@ func TestSeverity(t *testing.T) {
 		})
 	}
 }
+
+var OrderTestCases = []struct {
+	name      string
+	line      int
+	log       log0.Logger
+	input     []byte
+	kv        []log0.KV
+	expected  string
+	benchmark bool
+}{
+	{
+		name: "alphabetical order",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.Strings("version", "1.1"), log0.Strings("foo", "bar")},
+			Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		},
+		input:    []byte("Hello, World!"),
+		kv:       []log0.KV{log0.Strings("baz", "xyz")},
+		expected: `{"baz":"xyz","foo":"bar","message":"Hello, World!","version":"1.1"}` + "\n",
+	},
+	{
+		name: "insertion order",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.Strings("version", "1.1"), log0.Strings("foo", "bar")},
+			Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+			Order:  log0.Insertion,
+		},
+		input:    []byte("Hello, World!"),
+		kv:       []log0.KV{log0.Strings("baz", "xyz")},
+		expected: `{"version":"1.1","foo":"bar","baz":"xyz","message":"Hello, World!"}` + "\n",
+	},
+	{
+		name: "insertion order overridden value replaces the previous one in place",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.Strings("foo", "bar"), log0.Strings("baz", "xyz")},
+			Order:  log0.Insertion,
+		},
+		kv:       []log0.KV{log0.Strings("foo", "abc"), log0.Strings("foo", "def")},
+		expected: `{"foo":"def","baz":"xyz"}` + "\n",
+	},
+	{
+		name: "priority keys written first",
+		line: line(),
+		log: &log0.Log{
+			Output:   &bytes.Buffer{},
+			KV:       []log0.KV{log0.Strings("foo", "bar"), log0.Strings("level", "6"), log0.Strings("time", "now")},
+			Keys:     [4]encoding.TextMarshaler{log0.String("message")},
+			Priority: []encoding.TextMarshaler{log0.String("time"), log0.String("level"), log0.String("message")},
+		},
+		input:     []byte("Hello, World!"),
+		expected:  `{"time":"now","level":"6","message":"Hello, World!","foo":"bar"}` + "\n",
+		benchmark: true,
+	},
+	{
+		name: "absent priority key is skipped",
+		line: line(),
+		log: &log0.Log{
+			Output:   &bytes.Buffer{},
+			KV:       []log0.KV{log0.Strings("foo", "bar"), log0.Strings("baz", "xyz")},
+			Order:    log0.Insertion,
+			Priority: []encoding.TextMarshaler{log0.String("time"), log0.String("baz")},
+		},
+		expected: `{"baz":"xyz","foo":"bar"}` + "\n",
+	},
+}
+
+func TestOrder(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range OrderTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("order %s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			l0, ok := tc.log.(*log0.Log)
+			if !ok {
+				t.Fatal("unexpected logger type")
+			}
+
+			buf, ok := l0.Output.(*bytes.Buffer)
+			if !ok {
+				t.Fatal("unexpected output type")
+			}
+
+			*buf = bytes.Buffer{}
+
+			l := tc.log.Get(tc.kv...)
+			defer l.Put()
+
+			_, err := l.Write(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("unexpected output, expected: %q, recieved: %q %s", tc.expected, buf.String(), linkToExample)
+			}
+		})
+	}
+}
+
+var LevelTestCases = []struct {
+	name      string
+	line      int
+	log       log0.Logger
+	write     func(log0.Logger) error
+	expected  string
+	benchmark bool
+}{
+	{
+		name:  "emergency",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Emergency("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"0"
+		}`,
+	},
+	{
+		name:  "alert",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Alert("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"1"
+		}`,
+	},
+	{
+		name:  "critical",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Critical("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"2"
+		}`,
+	},
+	{
+		name:  "error",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Error("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"3"
+		}`,
+	},
+	{
+		name:  "warning",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Warning("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"4"
+		}`,
+	},
+	{
+		name:  "notice",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Notice("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"5"
+		}`,
+	},
+	{
+		name:  "info",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Info("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"6"
+		}`,
+	},
+	{
+		name:  "debug",
+		line:  line(),
+		log:   dummy(),
+		write: func(l log0.Logger) error { return l.Debug("Hello,\nWorld!") },
+		expected: `{
+			"message":"Hello,\nWorld!",
+			"excerpt":"Hello, World!",
+			"severity":"7"
+		}`,
+	},
+	{
+		name: "info with key-values overrides severity and permanent key-values",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.Strings("foo", "bar"), log0.Strings("severity", "42")},
+			Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		},
+		write: func(l log0.Logger) error {
+			return l.Info("Hello, World!", log0.Strings("foo", "baz"), log0.StringInt("xyz", 1))
+		},
+		expected: `{
+			"message":"Hello, World!",
+			"severity":"6",
+			"foo":"baz",
+			"xyz":1
+		}`,
+		benchmark: true,
+	},
+	{
+		name: "info with namespace writes severity outside of the namespace",
+		line: line(),
+		log: &log0.Log{
+			Output: &bytes.Buffer{},
+			KV:     []log0.KV{log0.Strings("svc", "a"), log0.StringNamespace("http"), log0.StringInt("status", 200)},
+			Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		},
+		write: func(l log0.Logger) error { return l.Info("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"svc":"a",
+			"http":{"status":200},
+			"severity":"6"
+		}`,
+	},
+	{
+		name: "custom severity key",
+		line: line(),
+		log: &log0.Log{
+			Output:      &bytes.Buffer{},
+			Keys:        [4]encoding.TextMarshaler{log0.String("message")},
+			SeverityKey: log0.String("level"),
+		},
+		write: func(l log0.Logger) error { return l.Warning("Hello, World!") },
+		expected: `{
+			"message":"Hello, World!",
+			"level":"4"
+		}`,
+	},
+}
+
+func TestLevel(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range LevelTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("level %s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			l0, ok := tc.log.(*log0.Log)
+			if !ok {
+				t.Fatal("unexpected logger type")
+			}
+
+			buf, ok := l0.Output.(*bytes.Buffer)
+			if !ok {
+				t.Fatal("unexpected output type")
+			}
+
+			*buf = bytes.Buffer{}
+
+			err := tc.write(tc.log)
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			ja := jsonassert.New(testprinter{t: t, link: linkToExample})
+			ja.Assertf(buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestLevelSeverityOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	l := &log0.Log{
+		Output: &out,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Severity: func(severity string) io.Writer {
+			if severity <= "3" {
+				return &errOut
+			}
+			return nil
+		},
+	}
+
+	err := l.Info("foo")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	err = l.Error("bar")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(out.String(), `{"message":"foo","severity":"6"}`)
+	ja.Assertf(errOut.String(), `{"message":"bar","severity":"3"}`)
+}
+
+func BenchmarkLevel(b *testing.B) {
+	for _, tc := range LevelTestCases {
+		if !tc.benchmark {
+			continue
+		}
+		b.Run(fmt.Sprintf("level %d", tc.line), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := tc.write(tc.log)
+				if err != nil {
+					fmt.Println(err)
+				}
+			}
+		})
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	var buf bytes.Buffer
+
+	var calls int
+
+	l := &log0.Log{
+		Output:    &buf,
+		Keys:      [4]encoding.TextMarshaler{log0.String("message")},
+		Threshold: log0.LevelInfo,
+		KV: []log0.KV{log0.StringFunc("func", func() log0.KV {
+			calls++
+			return log0.Int(calls)
+		})},
+	}
+
+	if !l.Enabled(log0.LevelError) || !l.Enabled(log0.LevelInfo) || l.Enabled(log0.LevelDebug) {
+		t.Errorf("unexpected enabled levels for the %d threshold", l.Threshold)
+	}
+
+	err := l.Debug("foo")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	l1 := l.Get(log0.StringSeverity("severity", "7"))
+	defer l1.Put()
+
+	_, err = l1.Write([]byte("bar"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	if buf.Len() != 0 || calls != 0 {
+		t.Errorf("unexpected output of the entries below the threshold: %q, func calls: %d", buf.String(), calls)
+	}
+
+	err = l.Info("baz")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{"message":"baz","severity":"6","func":1}`)
+}
+
+type clock time.Time
+
+func (c clock) Now() time.Time { return time.Time(c) }
+
+var TimestampTestCases = []struct {
+	name     string
+	line     int
+	log      *log0.Log
+	expected string
+}{
+	{
+		name: "rfc3339nano is a default format",
+		line: line(),
+		log: &log0.Log{
+			Time:  log0.String("time"),
+			Clock: clock(time.Date(2020, time.October, 15, 18, 9, 0, 123456789, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":"2020-10-15T18:09:00.123456789Z"}`,
+	},
+	{
+		name: "rfc3339",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.RFC3339,
+			Clock:      clock(time.Date(2020, time.October, 15, 18, 9, 0, 123456789, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":"2020-10-15T18:09:00Z"}`,
+	},
+	{
+		name: "rfc3339 in location",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.RFC3339,
+			Location:   time.FixedZone("UTC+3", 3*60*60),
+			Clock:      clock(time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":"2020-10-15T21:09:00+03:00"}`,
+	},
+	{
+		name: "unix seconds with fraction",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.Unix,
+			Clock:      clock(time.Date(2020, time.October, 15, 18, 9, 0, 123400000, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":1602785340.1234}`,
+	},
+	{
+		name: "unix seconds without fraction",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.Unix,
+			Clock:      clock(time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":1602785340}`,
+	},
+	{
+		name: "unix seconds before epoch",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.Unix,
+			Clock:      clock(time.Unix(-2, 5e8)),
+		},
+		expected: `{"message":"Hello, World!","time":-1.5}`,
+	},
+	{
+		name: "unix milliseconds",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.UnixMilli,
+			Clock:      clock(time.Date(2020, time.October, 15, 18, 9, 0, 123456789, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":1602785340123}`,
+	},
+	{
+		name: "unix nanoseconds",
+		line: line(),
+		log: &log0.Log{
+			Time:       log0.String("time"),
+			TimeFormat: log0.UnixNano,
+			Clock:      clock(time.Date(2020, time.October, 15, 18, 9, 0, 123456789, time.UTC)),
+		},
+		expected: `{"message":"Hello, World!","time":1602785340123456789}`,
+	},
+	{
+		name: "key-value overrides timestamp",
+		line: line(),
+		log: &log0.Log{
+			Time:  log0.String("time"),
+			Clock: clock(time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)),
+			KV:    []log0.KV{log0.StringInt("time", 42)},
+		},
+		expected: `{"message":"Hello, World!","time":42}`,
+	},
+}
+
+func TestTimestamp(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range TimestampTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("timestamp %s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			var buf bytes.Buffer
+
+			l := *tc.log
+			l.Output = &buf
+			l.Keys = [4]encoding.TextMarshaler{log0.String("message")}
+
+			_, err := l.Write([]byte("Hello, World!"))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s", err)
+			}
+
+			ja := jsonassert.New(testprinter{t: t, link: linkToExample})
+			ja.Assertf(buf.String(), tc.expected)
+		})
+	}
+}
+
+func BenchmarkTimestamp(b *testing.B) {
+	for _, tc := range TimestampTestCases {
+		l := *tc.log
+		l.Output = io.Discard
+		l.Keys = [4]encoding.TextMarshaler{log0.String("message")}
+		p := []byte("Hello, World!")
+		b.Run(fmt.Sprintf("timestamp %d", tc.line), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := l.Write(p)
+				if err != nil {
+					fmt.Println(err)
+				}
+			}
+		})
+	}
+}
+
+func TestCaller(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message"), log0.String("excerpt")},
+		Caller: log0.CallerFields,
+	}
+
+	_, err := l.Write([]byte("foo"))
+	writeLine := line() - 1
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{
+		"message":"foo",
+		"file":%q,
+		"line":%d,
+		"function":"github.com/danil/log0_test.TestCaller"
+	}`, testFile, writeLine)
+
+	buf.Reset()
+
+	err = l.Info("bar")
+	infoLine := line() - 1
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja.Assertf(buf.String(), `{
+		"message":"bar",
+		"severity":"6",
+		"file":%q,
+		"line":%d,
+		"function":"github.com/danil/log0_test.TestCaller"
+	}`, testFile, infoLine)
+
+	buf.Reset()
+
+	l.CallerSkip = 2
+	std := log.New(l, "", 0)
+
+	std.Print("baz")
+	printLine := line() - 1
+
+	ja.Assertf(buf.String(), `{
+		"message":"baz\n",
+		"excerpt":"baz",
+		"file":%q,
+		"line":%d,
+		"function":"github.com/danil/log0_test.TestCaller"
+	}`, testFile, printLine)
+}
+
+func TestCallerObject(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:         &buf,
+		Keys:           [4]encoding.TextMarshaler{log0.String("message")},
+		Caller:         log0.CallerObject,
+		CallerKeys:     [4]encoding.TextMarshaler{log0.String("src"), nil, nil, log0.String("func")},
+		CallerTrimFunc: "github.com/danil/",
+	}
+
+	l1 := l.Get(log0.StringInt("foo", 42))
+	defer l1.Put()
+
+	_, err := l1.Write([]byte("Hello, World!"))
+	writeLine := line() - 1
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{
+		"message":"Hello, World!",
+		"foo":42,
+		"src":{
+			"file":%q,
+			"line":%d,
+			"func":"log0_test.TestCallerObject"
+		}
+	}`, testFile, writeLine)
+}
+
+func TestCallerTrim(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	// File path is absolute or is prefixed by the module path if built with -trimpath.
+	trimpath := strings.HasPrefix(testFile, "github.com/danil/log0/")
+
+	expectedModuleFile := testFile
+	if trimpath {
+		expectedModuleFile = "log0_test.go"
+	}
+
+	for _, tc := range []struct {
+		name     string
+		file     string
+		function string
+		expected [2]string
+	}{
+		{
+			name:     "module root directory and module path",
+			file:     filepath.Dir(testFile),
+			function: "github.com/danil",
+			expected: [2]string{"log0_test.go", "log0_test.TestCallerTrim"},
+		},
+		{
+			name:     "module path of the trimpath",
+			file:     "github.com/danil/log0",
+			function: "github.com/danil/log0_test",
+			expected: [2]string{expectedModuleFile, "TestCallerTrim"},
+		},
+		{
+			name:     "prefixes without separator",
+			file:     testFile[:len(testFile)-1],
+			function: "github.com/danil/log",
+			expected: [2]string{testFile, "github.com/danil/log0_test.TestCallerTrim"},
+		},
+	} {
+		var buf bytes.Buffer
+
+		l := &log0.Log{
+			Output:         &buf,
+			Keys:           [4]encoding.TextMarshaler{log0.String("message")},
+			Caller:         log0.CallerFields,
+			CallerTrimFile: tc.file,
+			CallerTrimFunc: tc.function,
+		}
+
+		_, err := l.Write([]byte("Hello, World!"))
+		writeLine := line() - 1
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+
+		ja := jsonassert.New(testprinter{t: t, link: tc.name})
+		ja.Assertf(buf.String(), `{
+			"message":"Hello, World!",
+			"file":%q,
+			"line":%d,
+			"function":%q
+		}`, tc.expected[0], writeLine, tc.expected[1])
+	}
+}
+
+func BenchmarkCaller(b *testing.B) {
+	l := &log0.Log{
+		Output: io.Discard,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Caller: log0.CallerObject,
+	}
+	p := []byte("Hello, World!")
+	for i := 0; i < b.N; i++ {
+		_, err := l.Write(p)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
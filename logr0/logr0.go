@@ -0,0 +1,135 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package logr0 implements go-logr sink backed by the log0 logger.
+package logr0
+
+import (
+	"fmt"
+
+	"github.com/danil/log0"
+	"github.com/go-logr/logr"
+)
+
+// NewLogSink returns logr sink which writes entries through the logger.
+// Verbosity level 0 is written with the info severity level,
+// higher verbosity levels up to the verbosity are written
+// with the debug severity level, verbosity levels above are not written.
+// Names are joined with a dot and written with the "logger" key,
+// errors are written with the "error" key.
+// Caller of the log0.Log is a caller of the logr.Logger method.
+func NewLogSink(l log0.Logger, verbosity int) logr.LogSink {
+	return &logSink{l: l, verbosity: verbosity}
+}
+
+// New returns logr logger backed by the log0 logger.
+func New(l log0.Logger, verbosity int) logr.Logger {
+	return logr.New(NewLogSink(l, verbosity))
+}
+
+type logSink struct {
+	l         log0.Logger // l is a logger with key-values of the WithValues and WithName.
+	name      string      // name is a dotted name of the logger.
+	verbosity int         // verbosity is a maximum written verbosity level.
+	depth     int         // depth is a number of the stack frames between the sink method and the caller of the logr.
+}
+
+// Level returns severity level of the verbosity level.
+func Level(level int) log0.Level {
+	if level <= 0 {
+		return log0.LevelInfo
+	}
+	return log0.LevelDebug
+}
+
+func (s *logSink) Init(info logr.RuntimeInfo) {
+	s.depth = 1 + info.CallDepth
+}
+
+func (s *logSink) Enabled(level int) bool {
+	return level <= s.verbosity && s.l.Enabled(Level(level))
+}
+
+func (s *logSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if level > s.verbosity {
+		return
+	}
+
+	l := s.logger()
+	defer l.Put()
+
+	kv := appendKV(nil, keysAndValues)
+	if Level(level) == log0.LevelInfo {
+		_ = l.Info(msg, kv...)
+		return
+	}
+	_ = l.Debug(msg, kv...)
+}
+
+func (s *logSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	l := s.logger()
+	defer l.Put()
+
+	kv := append([]log0.KV{log0.StringError("error", err)}, appendKV(nil, keysAndValues)...)
+	_ = l.Error(msg, kv...)
+}
+
+// logger returns copy of the logger which writes caller of the logr.
+func (s *logSink) logger() log0.Logger {
+	l := s.l.Get()
+	if l0, ok := l.(*log0.Log); ok {
+		l0.CallerSkip += s.depth
+	}
+	return l
+}
+
+func (s *logSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	kv := appendKV(nil, keysAndValues)
+	if len(kv) == 0 {
+		return s
+	}
+	s0 := *s
+	s0.l = s.l.Get(kv...)
+	return &s0
+}
+
+func (s *logSink) WithName(name string) logr.LogSink {
+	if s.name != "" {
+		name = s.name + "." + name
+	}
+	s0 := *s
+	s0.l = s.l.Get(log0.Strings("logger", name))
+	s0.name = name
+	return &s0
+}
+
+func (s *logSink) WithCallDepth(depth int) logr.LogSink {
+	s0 := *s
+	s0.depth += depth
+	return &s0
+}
+
+// appendKV appends key-values of the logr alternating keys and values.
+// Value of the last key without value is the "<no-value>".
+func appendKV(kv []log0.KV, keysAndValues []interface{}) []log0.KV {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		k, ok := keysAndValues[i].(string)
+		if !ok {
+			k = fmt.Sprint(keysAndValues[i])
+		}
+
+		if i+1 == len(keysAndValues) {
+			kv = append(kv, log0.Strings(k, "<no-value>"))
+			break
+		}
+
+		switch v := keysAndValues[i+1].(type) {
+		case error:
+			kv = append(kv, log0.StringError(k, v))
+		default:
+			kv = append(kv, log0.StringAny(k, v))
+		}
+	}
+	return kv
+}
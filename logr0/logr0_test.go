@@ -0,0 +1,130 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package logr0_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/logr0"
+	"github.com/go-logr/logr"
+	"github.com/kinbiko/jsonassert"
+)
+
+func TestLogSink(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:         &buf,
+		Keys:           [4]encoding.TextMarshaler{log0.String("message")},
+		SeverityFormat: log0.SeverityName,
+		Threshold:      log0.LevelInfo,
+		Order:          log0.Insertion,
+	}
+
+	logger := logr0.New(l, 4).WithName("foo").WithValues("service", "api").WithName("bar")
+
+	logger.V(1).Info("debug")
+
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output below the threshold: %s", buf.String())
+	}
+
+	if logger.V(1).Enabled() || !logger.Enabled() {
+		t.Error("unexpected enabled verbosity levels")
+	}
+
+	logger.Info("Hello, World!", "count", 42, "odd")
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{
+		"message":"Hello, World!",
+		"severity":"info",
+		"logger":"foo.bar",
+		"service":"api",
+		"count":42,
+		"odd":"<no-value>"
+	}`)
+
+	buf.Reset()
+
+	logger.Error(errors.New("baz"), "Hello, Error!", "code", 7)
+
+	ja.Assertf(buf.String(), `{
+		"message":"Hello, Error!",
+		"severity":"error",
+		"logger":"foo.bar",
+		"service":"api",
+		"error":"baz",
+		"code":7
+	}`)
+}
+
+func TestLogSinkVerbosity(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+	}
+
+	logger := logr0.New(l, 2)
+
+	if !logger.V(2).Enabled() || logger.V(3).Enabled() {
+		t.Error("unexpected enabled verbosity levels")
+	}
+
+	logger.V(2).Info("foo")
+	logger.V(3).Info("bar")
+
+	if strings.Count(buf.String(), "\n") != 1 || !strings.Contains(buf.String(), `"foo"`) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+// helper is a logging helper which reports the caller of the helper.
+func helper(logger logr.Logger, msg string) {
+	logger.WithCallDepth(1).Info(msg)
+}
+
+func TestLogSinkCaller(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Caller: log0.CallerFields,
+	}
+
+	logger := logr0.New(l, 0).WithValues("service", "api")
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("foo")
+	helper(logger, "bar")
+	logger.Error(errors.New("baz"), "xyz")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+
+	for i, p := range lines {
+		var m map[string]interface{}
+		err := json.Unmarshal([]byte(p), &m)
+		if err != nil {
+			t.Fatalf("unexpected unmarshal error: %s, json: %s", err, p)
+		}
+
+		if m["file"] != file || m["line"] != float64(line+1+i) {
+			t.Errorf("unexpected caller, expected: %s:%d, received: %s", file, line+1+i, p)
+		}
+	}
+}
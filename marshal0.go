@@ -18,6 +18,55 @@ import (
 	"github.com/danil/log0/encode0"
 )
 
+// appendText appends text of the v to the dst
+// through the TextAppender interface if the v implements it.
+func appendText(dst []byte, v encoding.TextMarshaler) ([]byte, error) {
+	if a, ok := v.(TextAppender); ok {
+		return a.AppendText(dst)
+	}
+	p, err := v.MarshalText()
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, p...), nil
+}
+
+// appendJSON appends JSON of the v to the dst
+// through the JSONAppender interface if the v implements it.
+func appendJSON(dst []byte, v json.Marshaler) ([]byte, error) {
+	if a, ok := v.(JSONAppender); ok {
+		return a.AppendJSON(dst)
+	}
+	p, err := v.MarshalJSON()
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, p...), nil
+}
+
+// appendQuoted appends JSON string or null if the p is nil.
+func appendQuoted(dst []byte, p []byte) []byte {
+	if p == nil {
+		return append(dst, "null"...)
+	}
+	dst = append(dst, '"')
+	dst = encode0.AppendBytes(dst, p)
+	return append(dst, '"')
+}
+
+// appendComplex appends complex number formatted as the fmt package does
+// by the %g verb but without of the parentheses.
+func appendComplex(dst []byte, v complex128, bitSize int) []byte {
+	dst = strconv.AppendFloat(dst, real(v), 'g', -1, bitSize)
+	i := len(dst)
+	dst = strconv.AppendFloat(dst, imag(v), 'g', -1, bitSize)
+	if dst[i] != '+' && dst[i] != '-' {
+		dst = append(dst[:i+1], dst[i:]...)
+		dst[i] = '+'
+	}
+	return append(dst, 'i')
+}
+
 // Bool returns stringer/JSON marshaler interface implementation for the bool type.
 func Bool(v bool) boolV { return boolV{V: v} }
 
@@ -29,14 +78,22 @@ func (v boolV) String() string {
 }
 
 func (v boolV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v boolV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v boolV) AppendText(dst []byte) ([]byte, error) {
 	if v.V {
-		return []byte("true"), nil
+		return append(dst, "true"...), nil
 	}
-	return []byte("false"), nil
+	return append(dst, "false"...), nil
 }
 
-func (v boolV) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+func (v boolV) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Bool returns stringer/JSON marshaler interface implementation for the pointer to the bool type.
@@ -50,14 +107,25 @@ func (p boolP) String() string {
 }
 
 func (p boolP) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p boolP) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p boolP) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return boolV{V: *p.P}.MarshalText()
+	return boolV{V: *p.P}.AppendText(dst)
 }
 
-func (p boolP) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+func (p boolP) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return boolV{V: *p.P}.AppendJSON(dst)
 }
 
 var bufPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
@@ -73,31 +141,22 @@ func (v bytesV) String() string {
 }
 
 func (v bytesV) MarshalText() ([]byte, error) {
-	if v.V == nil {
-		return []byte("null"), nil
-	}
-
-	var buf bytes.Buffer
-
-	err := encode0.Bytes(&buf, v.V)
-	if err != nil {
-		return nil, err
-	}
-
-	return buf.Bytes(), nil
+	return v.AppendText(nil)
 }
 
 func (v bytesV) MarshalJSON() ([]byte, error) {
-	if v.V == nil {
-		return []byte("null"), nil
-	}
+	return v.AppendJSON(nil)
+}
 
-	p, err := v.MarshalText()
-	if err != nil {
-		return nil, err
+func (v bytesV) AppendText(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
 	}
+	return encode0.AppendBytes(dst, v.V), nil
+}
 
-	return append([]byte(`"`), append(p, []byte(`"`)...)...), nil
+func (v bytesV) AppendJSON(dst []byte) ([]byte, error) {
+	return appendQuoted(dst, v.V), nil
 }
 
 // Bytesp returns stringer/JSON marshaler interface implementation for the pointer to the byte slice type.
@@ -111,17 +170,25 @@ func (p bytesP) String() string {
 }
 
 func (p bytesP) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p bytesP) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p bytesP) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return bytesV{V: *p.P}.MarshalText()
+	return bytesV{V: *p.P}.AppendText(dst)
 }
 
-func (p bytesP) MarshalJSON() ([]byte, error) {
+func (p bytesP) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return bytesV{V: *p.P}.MarshalJSON()
+	return bytesV{V: *p.P}.AppendJSON(dst)
 }
 
 // Complex128 returns stringer/JSON marshaler interface implementation for the complex128 type.
@@ -135,11 +202,21 @@ func (v complex128V) String() string {
 }
 
 func (v complex128V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v complex128V) MarshalJSON() ([]byte, error) {
-	return append([]byte(`"`), append([]byte(v.String()), []byte(`"`)...)...), nil
+	return v.AppendJSON(nil)
+}
+
+func (v complex128V) AppendText(dst []byte) ([]byte, error) {
+	return appendComplex(dst, v.V, 64), nil
+}
+
+func (v complex128V) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	dst = appendComplex(dst, v.V, 64)
+	return append(dst, '"'), nil
 }
 
 // Complex128p returns stringer/JSON marshaler interface implementation for the pointer to the complex128 type.
@@ -155,17 +232,25 @@ func (p complex128P) String() string {
 }
 
 func (p complex128P) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p complex128P) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p complex128P) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return complex128V{V: *p.P}.MarshalText()
+	return complex128V{V: *p.P}.AppendText(dst)
 }
 
-func (p complex128P) MarshalJSON() ([]byte, error) {
+func (p complex128P) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return complex128V{V: *p.P}.MarshalJSON()
+	return complex128V{V: *p.P}.AppendJSON(dst)
 }
 
 // Complex64 returns stringer/JSON marshaler interface implementation for the complex64 type.
@@ -179,11 +264,21 @@ func (v complex64V) String() string {
 }
 
 func (v complex64V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v complex64V) MarshalJSON() ([]byte, error) {
-	return append([]byte(`"`), append([]byte(v.String()), []byte(`"`)...)...), nil
+	return v.AppendJSON(nil)
+}
+
+func (v complex64V) AppendText(dst []byte) ([]byte, error) {
+	return appendComplex(dst, complex128(v.V), 32), nil
+}
+
+func (v complex64V) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	dst = appendComplex(dst, complex128(v.V), 32)
+	return append(dst, '"'), nil
 }
 
 // Complex64p returns stringer/JSON marshaler interface implementation for the pointer to the complex64 type.
@@ -199,17 +294,25 @@ func (p complex64P) String() string {
 }
 
 func (p complex64P) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p complex64P) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p complex64P) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return complex64V{V: *p.P}.MarshalText()
+	return complex64V{V: *p.P}.AppendText(dst)
 }
 
-func (p complex64P) MarshalJSON() ([]byte, error) {
+func (p complex64P) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return complex64V{V: *p.P}.MarshalJSON()
+	return complex64V{V: *p.P}.AppendJSON(dst)
 }
 
 // Error returns stringer/JSON marshaler interface implementation for the error type.
@@ -223,31 +326,27 @@ func (v errorV) String() string {
 }
 
 func (v errorV) MarshalText() ([]byte, error) {
-	if v.V == nil {
-		return []byte("null"), nil
-	}
-
-	var buf bytes.Buffer
-
-	err := encode0.String(&buf, v.V.Error())
-	if err != nil {
-		return nil, err
-	}
-
-	return buf.Bytes(), nil
+	return v.AppendText(nil)
 }
 
 func (v errorV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v errorV) AppendText(dst []byte) ([]byte, error) {
 	if v.V == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
+	return encode0.AppendString(dst, v.V.Error()), nil
+}
 
-	p, err := v.MarshalText()
-	if err != nil {
-		return nil, err
+func (v errorV) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
 	}
-
-	return append([]byte(`"`), append(p, []byte(`"`)...)...), nil
+	dst = append(dst, '"')
+	dst = encode0.AppendString(dst, v.V.Error())
+	return append(dst, '"'), nil
 }
 
 // Float32 returns stringer/JSON marshaler interface implementation for the float32 type.
@@ -260,11 +359,19 @@ func (v float32V) String() string {
 }
 
 func (v float32V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v float32V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v float32V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendFloat(dst, float64(v.V), 'g', -1, 32), nil
+}
+
+func (v float32V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Float32p returns stringer/JSON marshaler interface implementation for the pointer to the float32 type.
@@ -280,14 +387,25 @@ func (p float32P) String() string {
 }
 
 func (p float32P) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p float32P) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p float32P) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return float32V{V: *p.P}.MarshalText()
+	return float32V{V: *p.P}.AppendText(dst)
 }
 
-func (p float32P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+func (p float32P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return float32V{V: *p.P}.AppendJSON(dst)
 }
 
 // Float64 returns stringer/JSON marshaler interface implementation for the float64 type.
@@ -300,11 +418,19 @@ func (v float64V) String() string {
 }
 
 func (v float64V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v float64V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v float64V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendFloat(dst, v.V, 'f', -1, 64), nil
+}
+
+func (v float64V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Float64p returns stringer/JSON marshaler interface implementation for the pointer to the float64 type.
@@ -320,11 +446,25 @@ func (p float64P) String() string {
 }
 
 func (p float64P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p float64P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p float64P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return float64V{V: *p.P}.AppendText(dst)
+}
+
+func (p float64P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return float64V{V: *p.P}.AppendJSON(dst)
 }
 
 // Int returns stringer/JSON marshaler interface implementation for the int type.
@@ -337,11 +477,19 @@ func (v intV) String() string {
 }
 
 func (v intV) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v intV) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v intV) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendInt(dst, int64(v.V), 10), nil
+}
+
+func (v intV) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Intp returns stringer/JSON marshaler interface implementation for the pointer to the int type.
@@ -357,11 +505,25 @@ func (p intP) String() string {
 }
 
 func (p intP) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p intP) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p intP) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return intV{V: *p.P}.AppendText(dst)
+}
+
+func (p intP) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return intV{V: *p.P}.AppendJSON(dst)
 }
 
 // Int16 returns stringer/JSON marshaler interface implementation for the int16 type.
@@ -374,11 +536,19 @@ func (v int16V) String() string {
 }
 
 func (v int16V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v int16V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v int16V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendInt(dst, int64(v.V), 10), nil
+}
+
+func (v int16V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Int16p returns stringer/JSON marshaler interface implementation for the pointer to the int16 type.
@@ -394,11 +564,25 @@ func (p int16P) String() string {
 }
 
 func (p int16P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p int16P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p int16P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int16V{V: *p.P}.AppendText(dst)
+}
+
+func (p int16P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int16V{V: *p.P}.AppendJSON(dst)
 }
 
 // Int32 returns stringer/JSON marshaler interface implementation for the int32 type.
@@ -411,11 +595,19 @@ func (v int32V) String() string {
 }
 
 func (v int32V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v int32V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v int32V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendInt(dst, int64(v.V), 10), nil
+}
+
+func (v int32V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Int32p returns stringer/JSON marshaler interface implementation for the pointer to the int32 type.
@@ -431,11 +623,25 @@ func (p int32P) String() string {
 }
 
 func (p int32P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p int32P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p int32P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int32V{V: *p.P}.AppendText(dst)
+}
+
+func (p int32P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int32V{V: *p.P}.AppendJSON(dst)
 }
 
 // Int64 returns stringer/JSON marshaler interface implementation for the int64 type.
@@ -448,11 +654,19 @@ func (v int64V) String() string {
 }
 
 func (v int64V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v int64V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v int64V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendInt(dst, v.V, 10), nil
+}
+
+func (v int64V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Int64p returns stringer/JSON marshaler interface implementation for the pointer to the int64 type.
@@ -468,11 +682,25 @@ func (p int64P) String() string {
 }
 
 func (p int64P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p int64P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p int64P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int64V{V: *p.P}.AppendText(dst)
+}
+
+func (p int64P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int64V{V: *p.P}.AppendJSON(dst)
 }
 
 // Int8 returns stringer/JSON marshaler interface implementation for the int8 type.
@@ -485,11 +713,19 @@ func (v int8V) String() string {
 }
 
 func (v int8V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v int8V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v int8V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendInt(dst, int64(v.V), 10), nil
+}
+
+func (v int8V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Int8p returns stringer/JSON marshaler interface implementation for the pointer to the int8 type.
@@ -505,11 +741,25 @@ func (p int8P) String() string {
 }
 
 func (p int8P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p int8P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p int8P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int8V{V: *p.P}.AppendText(dst)
+}
+
+func (p int8P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return int8V{V: *p.P}.AppendJSON(dst)
 }
 
 // Runes returns stringer/JSON marshaler interface implementation for the rune slice type.
@@ -535,31 +785,27 @@ func (v runesV) String() string {
 }
 
 func (v runesV) MarshalText() ([]byte, error) {
-	if v.V == nil {
-		return []byte("null"), nil
-	}
-
-	var buf bytes.Buffer
-
-	err := encode0.Runes(&buf, v.V)
-	if err != nil {
-		return nil, err
-	}
-
-	return buf.Bytes(), nil
+	return v.AppendText(nil)
 }
 
 func (v runesV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v runesV) AppendText(dst []byte) ([]byte, error) {
 	if v.V == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
+	return encode0.AppendRunes(dst, v.V), nil
+}
 
-	p, err := v.MarshalText()
-	if err != nil {
-		return nil, err
+func (v runesV) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
 	}
-
-	return append([]byte(`"`), append(p, []byte(`"`)...)...), nil
+	dst = append(dst, '"')
+	dst = encode0.AppendRunes(dst, v.V)
+	return append(dst, '"'), nil
 }
 
 // Runesp returns stringer/JSON marshaler interface implementation for the pointer to the rune slice type.
@@ -575,17 +821,25 @@ func (p runesP) String() string {
 }
 
 func (p runesP) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p runesP) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p runesP) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return runesV{V: *p.P}.MarshalText()
+	return runesV{V: *p.P}.AppendText(dst)
 }
 
-func (p runesP) MarshalJSON() ([]byte, error) {
+func (p runesP) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return runesV{V: *p.P}.MarshalJSON()
+	return runesV{V: *p.P}.AppendJSON(dst)
 }
 
 // String returns stringer/JSON marshaler interface implementation for the string type.
@@ -607,20 +861,21 @@ func (v stringV) String() string {
 }
 
 func (v stringV) MarshalText() ([]byte, error) {
-	var buf bytes.Buffer
-	err := encode0.String(&buf, v.V)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
+	return v.AppendText(nil)
 }
 
 func (v stringV) MarshalJSON() ([]byte, error) {
-	p, err := v.MarshalText()
-	if err != nil {
-		return nil, err
-	}
-	return append([]byte(`"`), append(p, []byte(`"`)...)...), nil
+	return v.AppendJSON(nil)
+}
+
+func (v stringV) AppendText(dst []byte) ([]byte, error) {
+	return encode0.AppendString(dst, v.V), nil
+}
+
+func (v stringV) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	dst = encode0.AppendString(dst, v.V)
+	return append(dst, '"'), nil
 }
 
 // Stringp returns stringer/JSON marshaler interface implementation for the pointer to the string type.
@@ -636,17 +891,25 @@ func (p stringP) String() string {
 }
 
 func (p stringP) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p stringP) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p stringP) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return stringV{V: *p.P}.MarshalText()
+	return stringV{V: *p.P}.AppendText(dst)
 }
 
-func (p stringP) MarshalJSON() ([]byte, error) {
+func (p stringP) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return stringV{V: *p.P}.MarshalJSON()
+	return stringV{V: *p.P}.AppendJSON(dst)
 }
 
 // Text returns stringer/JSON marshaler interface implementation for the encoding.TextMarshaler type.
@@ -673,27 +936,28 @@ func (v textV) String() string {
 }
 
 func (v textV) MarshalText() ([]byte, error) {
-	p, err := v.V.MarshalText()
-	if err != nil {
-		return nil, err
-	}
+	return v.AppendText(nil)
+}
 
-	var buf bytes.Buffer
+func (v textV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
 
-	err = encode0.Bytes(&buf, p)
+func (v textV) AppendText(dst []byte) ([]byte, error) {
+	p, err := v.V.MarshalText()
 	if err != nil {
-		return nil, err
+		return dst, err
 	}
-
-	return buf.Bytes(), nil
+	return encode0.AppendBytes(dst, p), nil
 }
 
-func (v textV) MarshalJSON() ([]byte, error) {
-	p, err := v.MarshalText()
+func (v textV) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	dst, err := v.AppendText(dst)
 	if err != nil {
-		return nil, err
+		return dst, err
 	}
-	return append([]byte(`"`), append(p, []byte(`"`)...)...), nil
+	return append(dst, '"'), nil
 }
 
 // Uint returns stringer/JSON marshaler interface implementation for the uint type.
@@ -706,11 +970,19 @@ func (v uintV) String() string {
 }
 
 func (v uintV) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v uintV) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v uintV) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, uint64(v.V), 10), nil
+}
+
+func (v uintV) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Uintp returns stringer/JSON marshaler interface implementation for the pointer to the uint type.
@@ -726,11 +998,25 @@ func (p uintP) String() string {
 }
 
 func (p uintP) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p uintP) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p uintP) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uintV{V: *p.P}.AppendText(dst)
+}
+
+func (p uintP) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uintV{V: *p.P}.AppendJSON(dst)
 }
 
 // Uint16 returns stringer/JSON marshaler interface implementation for the uint16 type.
@@ -743,11 +1029,19 @@ func (v uint16V) String() string {
 }
 
 func (v uint16V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v uint16V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v uint16V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, uint64(v.V), 10), nil
+}
+
+func (v uint16V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Uint16p returns stringer/JSON marshaler interface implementation for the pointer to the uint16 type.
@@ -763,11 +1057,25 @@ func (p uint16P) String() string {
 }
 
 func (p uint16P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p uint16P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p uint16P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint16V{V: *p.P}.AppendText(dst)
+}
+
+func (p uint16P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint16V{V: *p.P}.AppendJSON(dst)
 }
 
 // Uint32 returns stringer/JSON marshaler interface implementation for the uint32 type.
@@ -780,11 +1088,19 @@ func (v uint32V) String() string {
 }
 
 func (v uint32V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v uint32V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v uint32V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, uint64(v.V), 10), nil
+}
+
+func (v uint32V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Uint32p returns stringer/JSON marshaler interface implementation for the pointer to the uint32 type.
@@ -800,11 +1116,25 @@ func (p uint32P) String() string {
 }
 
 func (p uint32P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p uint32P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p uint32P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint32V{V: *p.P}.AppendText(dst)
+}
+
+func (p uint32P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint32V{V: *p.P}.AppendJSON(dst)
 }
 
 // Uint64 returns stringer/JSON marshaler interface implementation for the uint64 type.
@@ -817,11 +1147,19 @@ func (v uint64V) String() string {
 }
 
 func (v uint64V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v uint64V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v uint64V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, v.V, 10), nil
+}
+
+func (v uint64V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Uint64p returns stringer/JSON marshaler interface implementation for the pointer to the uint64 type.
@@ -837,15 +1175,29 @@ func (p uint64P) String() string {
 }
 
 func (p uint64P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p uint64P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
 }
 
-// Uint8 returns stringer/JSON marshaler interface implementation for the uint8 type.
-func Uint8(v uint8) uint8V { return uint8V{V: v} }
+func (p uint64P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint64V{V: *p.P}.AppendText(dst)
+}
+
+func (p uint64P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint64V{V: *p.P}.AppendJSON(dst)
+}
+
+// Uint8 returns stringer/JSON marshaler interface implementation for the uint8 type.
+func Uint8(v uint8) uint8V { return uint8V{V: v} }
 
 type uint8V struct{ V uint8 }
 
@@ -854,11 +1206,19 @@ func (v uint8V) String() string {
 }
 
 func (v uint8V) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v uint8V) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v uint8V) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, uint64(v.V), 10), nil
+}
+
+func (v uint8V) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Uint8p returns stringer/JSON marshaler interface implementation for the pointer to the uint8 type.
@@ -874,11 +1234,25 @@ func (p uint8P) String() string {
 }
 
 func (p uint8P) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p uint8P) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p uint8P) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint8V{V: *p.P}.AppendText(dst)
+}
+
+func (p uint8P) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uint8V{V: *p.P}.AppendJSON(dst)
 }
 
 // Uintptr returns stringer/JSON marshaler interface implementation for the uintptr type.
@@ -891,11 +1265,19 @@ func (v uintptrV) String() string {
 }
 
 func (v uintptrV) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v uintptrV) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+	return v.AppendJSON(nil)
+}
+
+func (v uintptrV) AppendText(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, uint64(v.V), 10), nil
+}
+
+func (v uintptrV) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
 }
 
 // Uintptrp returns stringer/JSON marshaler interface implementation for the pointer to the uintptr type.
@@ -911,11 +1293,25 @@ func (p uintptrP) String() string {
 }
 
 func (p uintptrP) MarshalText() ([]byte, error) {
-	return []byte(p.String()), nil
+	return p.AppendText(nil)
 }
 
 func (p uintptrP) MarshalJSON() ([]byte, error) {
-	return p.MarshalText()
+	return p.AppendJSON(nil)
+}
+
+func (p uintptrP) AppendText(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uintptrV{V: *p.P}.AppendText(dst)
+}
+
+func (p uintptrP) AppendJSON(dst []byte) ([]byte, error) {
+	if p.P == nil {
+		return append(dst, "null"...), nil
+	}
+	return uintptrV{V: *p.P}.AppendJSON(dst)
 }
 
 // Time returns stringer/JSON marshaler interface implementation for the time time type.
@@ -928,11 +1324,32 @@ func (v timeV) String() string {
 }
 
 func (v timeV) MarshalText() ([]byte, error) {
-	return v.V.MarshalText()
+	return v.AppendText(nil)
 }
 
 func (v timeV) MarshalJSON() ([]byte, error) {
-	return v.V.MarshalJSON()
+	return v.AppendJSON(nil)
+}
+
+func (v timeV) AppendText(dst []byte) ([]byte, error) {
+	// The time package reports an error for the years outside of the [0,9999].
+	if y := v.V.Year(); y < 0 || y >= 10000 {
+		p, err := v.V.MarshalText()
+		if err != nil {
+			return dst, err
+		}
+		return append(dst, p...), nil
+	}
+	return v.V.AppendFormat(dst, time.RFC3339Nano), nil
+}
+
+func (v timeV) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	dst, err := v.AppendText(dst)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, '"'), nil
 }
 
 // Timep returns stringer/JSON marshaler interface implementation for the pointer to the time time type.
@@ -948,17 +1365,25 @@ func (p timeP) String() string {
 }
 
 func (p timeP) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p timeP) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p timeP) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return timeV{V: *p.P}.MarshalText()
+	return timeV{V: *p.P}.AppendText(dst)
 }
 
-func (p timeP) MarshalJSON() ([]byte, error) {
+func (p timeP) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return timeV{V: *p.P}.MarshalJSON()
+	return timeV{V: *p.P}.AppendJSON(dst)
 }
 
 // Duration returns stringer/JSON marshaler interface implementation for the time duration type.
@@ -971,11 +1396,21 @@ func (v durationV) String() string {
 }
 
 func (v durationV) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v durationV) MarshalJSON() ([]byte, error) {
-	return append([]byte(`"`), append([]byte(v.String()), []byte(`"`)...)...), nil
+	return v.AppendJSON(nil)
+}
+
+func (v durationV) AppendText(dst []byte) ([]byte, error) {
+	return append(dst, v.V.String()...), nil
+}
+
+func (v durationV) AppendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	dst = append(dst, v.V.String()...)
+	return append(dst, '"'), nil
 }
 
 // Durationp returns stringer/JSON marshaler interface implementation for the pointer to the time duration type.
@@ -991,17 +1426,25 @@ func (p durationP) String() string {
 }
 
 func (p durationP) MarshalText() ([]byte, error) {
+	return p.AppendText(nil)
+}
+
+func (p durationP) MarshalJSON() ([]byte, error) {
+	return p.AppendJSON(nil)
+}
+
+func (p durationP) AppendText(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return durationV{V: *p.P}.MarshalText()
+	return durationV{V: *p.P}.AppendText(dst)
 }
 
-func (p durationP) MarshalJSON() ([]byte, error) {
+func (p durationP) AppendJSON(dst []byte) ([]byte, error) {
 	if p.P == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return durationV{V: *p.P}.MarshalJSON()
+	return durationV{V: *p.P}.AppendJSON(dst)
 }
 
 // Func returns stringer/JSON marshaler interface implementation for the custom func type.
@@ -1015,11 +1458,19 @@ func (v funcV) String() string {
 }
 
 func (v funcV) MarshalText() ([]byte, error) {
-	return v.V().MarshalText()
+	return v.AppendText(nil)
 }
 
 func (v funcV) MarshalJSON() ([]byte, error) {
-	return v.V().MarshalJSON()
+	return v.AppendJSON(nil)
+}
+
+func (v funcV) AppendText(dst []byte) ([]byte, error) {
+	return appendText(dst, v.V())
+}
+
+func (v funcV) AppendJSON(dst []byte) ([]byte, error) {
+	return appendJSON(dst, v.V())
 }
 
 // Raw returns stringer/JSON marshaler interface implementation for the raw byte slice.
@@ -1035,14 +1486,48 @@ func (v rawV) String() string {
 }
 
 func (v rawV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v rawV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v rawV) AppendText(dst []byte) ([]byte, error) {
 	if v.V == nil {
-		return []byte("null"), nil
+		return append(dst, "null"...), nil
 	}
-	return v.V, nil
+	return append(dst, v.V...), nil
 }
 
-func (v rawV) MarshalJSON() ([]byte, error) {
-	return v.MarshalText()
+func (v rawV) AppendJSON(dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
+}
+
+// Object returns stringer/JSON marshaler interface implementation for the nested JSON object of the key-values.
+func Object(v ...KV) objectV { return objectV{V: v} }
+
+type objectV struct{ V []KV }
+
+func (v objectV) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v objectV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v objectV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v objectV) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v objectV) AppendJSON(dst []byte) ([]byte, error) {
+	return appendObject(dst, v.V)
 }
 
 func Any(v interface{}) anyV { return anyV{V: v} }
@@ -1148,194 +1633,202 @@ func (v anyV) String() string {
 }
 
 func (v anyV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v anyV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v anyV) AppendText(dst []byte) ([]byte, error) {
 	switch x := v.V.(type) {
 	case bool:
-		return Bool(x).MarshalText()
+		return Bool(x).AppendText(dst)
 	case *bool:
-		return Boolp(x).MarshalText()
+		return Boolp(x).AppendText(dst)
 	case []byte:
-		return Bytes(x).MarshalText()
+		return Bytes(x).AppendText(dst)
 	case *[]byte:
-		return Bytesp(x).MarshalText()
+		return Bytesp(x).AppendText(dst)
 	case complex128:
-		return Complex128(x).MarshalText()
+		return Complex128(x).AppendText(dst)
 	case *complex128:
-		return Complex128p(x).MarshalText()
+		return Complex128p(x).AppendText(dst)
 	case complex64:
-		return Complex64(x).MarshalText()
+		return Complex64(x).AppendText(dst)
 	case *complex64:
-		return Complex64p(x).MarshalText()
+		return Complex64p(x).AppendText(dst)
 	case error:
-		return Error(x).MarshalText()
+		return Error(x).AppendText(dst)
 	case float32:
-		return Float32(x).MarshalText()
+		return Float32(x).AppendText(dst)
 	case *float32:
-		return Float32p(x).MarshalText()
+		return Float32p(x).AppendText(dst)
 	case float64:
-		return Float64(x).MarshalText()
+		return Float64(x).AppendText(dst)
 	case *float64:
-		return Float64p(x).MarshalText()
+		return Float64p(x).AppendText(dst)
 	case int:
-		return Int(x).MarshalText()
+		return Int(x).AppendText(dst)
 	case *int:
-		return Intp(x).MarshalText()
+		return Intp(x).AppendText(dst)
 	case int16:
-		return Int16(x).MarshalText()
+		return Int16(x).AppendText(dst)
 	case *int16:
-		return Int16p(x).MarshalText()
+		return Int16p(x).AppendText(dst)
 	case int32:
-		return Int32(x).MarshalText()
+		return Int32(x).AppendText(dst)
 	case *int32:
-		return Int32p(x).MarshalText()
+		return Int32p(x).AppendText(dst)
 	case int64:
-		return Int64(x).MarshalText()
+		return Int64(x).AppendText(dst)
 	case *int64:
-		return Int64p(x).MarshalText()
+		return Int64p(x).AppendText(dst)
 	case int8:
-		return Int8(x).MarshalText()
+		return Int8(x).AppendText(dst)
 	case *int8:
-		return Int8p(x).MarshalText()
+		return Int8p(x).AppendText(dst)
 	case []rune:
-		return Runes(x).MarshalText()
+		return Runes(x).AppendText(dst)
 	case *[]rune:
-		return Runesp(x).MarshalText()
+		return Runesp(x).AppendText(dst)
 	case string:
-		return String(x).MarshalText()
+		return String(x).AppendText(dst)
 	case *string:
-		return Stringp(x).MarshalText()
+		return Stringp(x).AppendText(dst)
 	case uint:
-		return Uint(x).MarshalText()
+		return Uint(x).AppendText(dst)
 	case *uint:
-		return Uintp(x).MarshalText()
+		return Uintp(x).AppendText(dst)
 	case uint16:
-		return Uint16(x).MarshalText()
+		return Uint16(x).AppendText(dst)
 	case *uint16:
-		return Uint16p(x).MarshalText()
+		return Uint16p(x).AppendText(dst)
 	case uint32:
-		return Uint32(x).MarshalText()
+		return Uint32(x).AppendText(dst)
 	case *uint32:
-		return Uint32p(x).MarshalText()
+		return Uint32p(x).AppendText(dst)
 	case uint64:
-		return Uint64(x).MarshalText()
+		return Uint64(x).AppendText(dst)
 	case *uint64:
-		return Uint64p(x).MarshalText()
+		return Uint64p(x).AppendText(dst)
 	case uint8:
-		return Uint8(x).MarshalText()
+		return Uint8(x).AppendText(dst)
 	case *uint8:
-		return Uint8p(x).MarshalText()
+		return Uint8p(x).AppendText(dst)
 	case uintptr:
-		return Uintptr(x).MarshalText()
+		return Uintptr(x).AppendText(dst)
 	case *uintptr:
-		return Uintptrp(x).MarshalText()
+		return Uintptrp(x).AppendText(dst)
 	case time.Time:
-		return Time(x).MarshalText()
+		return Time(x).AppendText(dst)
 	case *time.Time:
-		return Timep(x).MarshalText()
+		return Timep(x).AppendText(dst)
 	case time.Duration:
-		return Duration(x).MarshalText()
+		return Duration(x).AppendText(dst)
 	case *time.Duration:
-		return Durationp(x).MarshalText()
+		return Durationp(x).AppendText(dst)
 	case encoding.TextMarshaler:
-		return x.MarshalText()
+		return appendText(dst, x)
 	default:
-		return Reflect(x).MarshalText()
+		return Reflect(x).AppendText(dst)
 	}
 }
 
-func (v anyV) MarshalJSON() ([]byte, error) {
+func (v anyV) AppendJSON(dst []byte) ([]byte, error) {
 	switch x := v.V.(type) {
 	case bool:
-		return Bool(x).MarshalJSON()
+		return Bool(x).AppendJSON(dst)
 	case *bool:
-		return Boolp(x).MarshalJSON()
+		return Boolp(x).AppendJSON(dst)
 	case []byte:
-		return Bytes(x).MarshalJSON()
+		return Bytes(x).AppendJSON(dst)
 	case *[]byte:
-		return Bytesp(x).MarshalJSON()
+		return Bytesp(x).AppendJSON(dst)
 	case complex128:
-		return Complex128(x).MarshalJSON()
+		return Complex128(x).AppendJSON(dst)
 	case *complex128:
-		return Complex128p(x).MarshalJSON()
+		return Complex128p(x).AppendJSON(dst)
 	case complex64:
-		return Complex64(x).MarshalJSON()
+		return Complex64(x).AppendJSON(dst)
 	case *complex64:
-		return Complex64p(x).MarshalJSON()
+		return Complex64p(x).AppendJSON(dst)
 	case error:
-		return Error(x).MarshalJSON()
+		return Error(x).AppendJSON(dst)
 	case float32:
-		return Float32(x).MarshalJSON()
+		return Float32(x).AppendJSON(dst)
 	case *float32:
-		return Float32p(x).MarshalJSON()
+		return Float32p(x).AppendJSON(dst)
 	case float64:
-		return Float64(x).MarshalJSON()
+		return Float64(x).AppendJSON(dst)
 	case *float64:
-		return Float64p(x).MarshalJSON()
+		return Float64p(x).AppendJSON(dst)
 	case int:
-		return Int(x).MarshalJSON()
+		return Int(x).AppendJSON(dst)
 	case *int:
-		return Intp(x).MarshalJSON()
+		return Intp(x).AppendJSON(dst)
 	case int16:
-		return Int16(x).MarshalJSON()
+		return Int16(x).AppendJSON(dst)
 	case *int16:
-		return Int16p(x).MarshalJSON()
+		return Int16p(x).AppendJSON(dst)
 	case int32:
-		return Int32(x).MarshalJSON()
+		return Int32(x).AppendJSON(dst)
 	case *int32:
-		return Int32p(x).MarshalJSON()
+		return Int32p(x).AppendJSON(dst)
 	case int64:
-		return Int64(x).MarshalJSON()
+		return Int64(x).AppendJSON(dst)
 	case *int64:
-		return Int64p(x).MarshalJSON()
+		return Int64p(x).AppendJSON(dst)
 	case int8:
-		return Int8(x).MarshalJSON()
+		return Int8(x).AppendJSON(dst)
 	case *int8:
-		return Int8p(x).MarshalJSON()
+		return Int8p(x).AppendJSON(dst)
 	case []rune:
-		return Runes(x).MarshalJSON()
+		return Runes(x).AppendJSON(dst)
 	case *[]rune:
-		return Runesp(x).MarshalJSON()
+		return Runesp(x).AppendJSON(dst)
 	case string:
-		return String(x).MarshalJSON()
+		return String(x).AppendJSON(dst)
 	case *string:
-		return Stringp(x).MarshalJSON()
+		return Stringp(x).AppendJSON(dst)
 	case uint:
-		return Uint(x).MarshalJSON()
+		return Uint(x).AppendJSON(dst)
 	case *uint:
-		return Uintp(x).MarshalJSON()
+		return Uintp(x).AppendJSON(dst)
 	case uint16:
-		return Uint16(x).MarshalJSON()
+		return Uint16(x).AppendJSON(dst)
 	case *uint16:
-		return Uint16p(x).MarshalJSON()
+		return Uint16p(x).AppendJSON(dst)
 	case uint32:
-		return Uint32(x).MarshalJSON()
+		return Uint32(x).AppendJSON(dst)
 	case *uint32:
-		return Uint32p(x).MarshalJSON()
+		return Uint32p(x).AppendJSON(dst)
 	case uint64:
-		return Uint64(x).MarshalJSON()
+		return Uint64(x).AppendJSON(dst)
 	case *uint64:
-		return Uint64p(x).MarshalJSON()
+		return Uint64p(x).AppendJSON(dst)
 	case uint8:
-		return Uint8(x).MarshalJSON()
+		return Uint8(x).AppendJSON(dst)
 	case *uint8:
-		return Uint8p(x).MarshalJSON()
+		return Uint8p(x).AppendJSON(dst)
 	case uintptr:
-		return Uintptr(x).MarshalJSON()
+		return Uintptr(x).AppendJSON(dst)
 	case *uintptr:
-		return Uintptrp(x).MarshalJSON()
+		return Uintptrp(x).AppendJSON(dst)
 	case time.Time:
-		return Time(x).MarshalJSON()
+		return Time(x).AppendJSON(dst)
 	case *time.Time:
-		return Timep(x).MarshalJSON()
+		return Timep(x).AppendJSON(dst)
 	case time.Duration:
-		return Duration(x).MarshalJSON()
+		return Duration(x).AppendJSON(dst)
 	case *time.Duration:
-		return Durationp(x).MarshalJSON()
+		return Durationp(x).AppendJSON(dst)
 	case encoding.TextMarshaler:
-		return Text(x).MarshalJSON()
+		return Text(x).AppendJSON(dst)
 	case json.Marshaler:
-		return x.MarshalJSON()
+		return appendJSON(dst, x)
 	default:
-		return Reflect(x).MarshalJSON()
+		return Reflect(x).AppendJSON(dst)
 	}
 }
 
@@ -1376,9 +1869,1017 @@ func (v reflectV) String() string {
 }
 
 func (v reflectV) MarshalText() ([]byte, error) {
-	return []byte(v.String()), nil
+	return v.AppendText(nil)
 }
 
 func (v reflectV) MarshalJSON() ([]byte, error) {
-	return json.Marshal(v.V)
+	return v.AppendJSON(nil)
+}
+
+func (v reflectV) AppendText(dst []byte) ([]byte, error) {
+	return append(dst, v.String()...), nil
+}
+
+func (v reflectV) AppendJSON(dst []byte) ([]byte, error) {
+	p, err := json.Marshal(v.V)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, p...), nil
+}
+
+// BoolSlice returns stringer/JSON marshaler interface implementation for the bool slice type.
+func BoolSlice(v []bool) boolS { return boolS{V: v} }
+
+type boolS struct{ V []bool }
+
+func (v boolS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v boolS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v boolS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v boolS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v boolS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Bool(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Complex128Slice returns stringer/JSON marshaler interface implementation for the complex128 slice type.
+func Complex128Slice(v []complex128) complex128S { return complex128S{V: v} }
+
+type complex128S struct{ V []complex128 }
+
+func (v complex128S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v complex128S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v complex128S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v complex128S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v complex128S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Complex128(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Complex64Slice returns stringer/JSON marshaler interface implementation for the complex64 slice type.
+func Complex64Slice(v []complex64) complex64S { return complex64S{V: v} }
+
+type complex64S struct{ V []complex64 }
+
+func (v complex64S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v complex64S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v complex64S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v complex64S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v complex64S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Complex64(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// DurationSlice returns stringer/JSON marshaler interface implementation for the time duration slice type.
+func DurationSlice(v []time.Duration) durationS { return durationS{V: v} }
+
+type durationS struct{ V []time.Duration }
+
+func (v durationS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v durationS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v durationS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v durationS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v durationS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Duration(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// ErrorSlice returns stringer/JSON marshaler interface implementation for the error slice type.
+func ErrorSlice(v []error) errorS { return errorS{V: v} }
+
+type errorS struct{ V []error }
+
+func (v errorS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v errorS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v errorS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v errorS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v errorS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Error(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Float32Slice returns stringer/JSON marshaler interface implementation for the float32 slice type.
+func Float32Slice(v []float32) float32S { return float32S{V: v} }
+
+type float32S struct{ V []float32 }
+
+func (v float32S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v float32S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v float32S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v float32S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v float32S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Float32(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Float64Slice returns stringer/JSON marshaler interface implementation for the float64 slice type.
+func Float64Slice(v []float64) float64S { return float64S{V: v} }
+
+type float64S struct{ V []float64 }
+
+func (v float64S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v float64S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v float64S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v float64S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v float64S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Float64(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// IntSlice returns stringer/JSON marshaler interface implementation for the int slice type.
+func IntSlice(v []int) intS { return intS{V: v} }
+
+type intS struct{ V []int }
+
+func (v intS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v intS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v intS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v intS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v intS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Int(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Int16Slice returns stringer/JSON marshaler interface implementation for the int16 slice type.
+func Int16Slice(v []int16) int16S { return int16S{V: v} }
+
+type int16S struct{ V []int16 }
+
+func (v int16S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v int16S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v int16S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v int16S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v int16S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Int16(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Int32Slice returns stringer/JSON marshaler interface implementation for the int32 slice type.
+func Int32Slice(v []int32) int32S { return int32S{V: v} }
+
+type int32S struct{ V []int32 }
+
+func (v int32S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v int32S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v int32S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v int32S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v int32S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Int32(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Int64Slice returns stringer/JSON marshaler interface implementation for the int64 slice type.
+func Int64Slice(v []int64) int64S { return int64S{V: v} }
+
+type int64S struct{ V []int64 }
+
+func (v int64S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v int64S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v int64S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v int64S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v int64S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Int64(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Int8Slice returns stringer/JSON marshaler interface implementation for the int8 slice type.
+func Int8Slice(v []int8) int8S { return int8S{V: v} }
+
+type int8S struct{ V []int8 }
+
+func (v int8S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v int8S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v int8S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v int8S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v int8S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Int8(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// StringSlice returns stringer/JSON marshaler interface implementation for the string slice type.
+func StringSlice(v []string) stringS { return stringS{V: v} }
+
+type stringS struct{ V []string }
+
+func (v stringS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v stringS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v stringS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v stringS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v stringS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = String(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// TextSlice returns stringer/JSON marshaler interface implementation for the encoding.TextMarshaler slice type.
+func TextSlice(v []encoding.TextMarshaler) textS { return textS{V: v} }
+
+type textS struct{ V []encoding.TextMarshaler }
+
+func (v textS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v textS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v textS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v textS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v textS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		if x == nil {
+			dst = append(dst, "null"...)
+			continue
+		}
+
+		dst, err = Text(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// TimeSlice returns stringer/JSON marshaler interface implementation for the time time slice type.
+func TimeSlice(v []time.Time) timeS { return timeS{V: v} }
+
+type timeS struct{ V []time.Time }
+
+func (v timeS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v timeS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v timeS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v timeS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v timeS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Time(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// UintSlice returns stringer/JSON marshaler interface implementation for the uint slice type.
+func UintSlice(v []uint) uintS { return uintS{V: v} }
+
+type uintS struct{ V []uint }
+
+func (v uintS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v uintS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v uintS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v uintS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v uintS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Uint(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Uint16Slice returns stringer/JSON marshaler interface implementation for the uint16 slice type.
+func Uint16Slice(v []uint16) uint16S { return uint16S{V: v} }
+
+type uint16S struct{ V []uint16 }
+
+func (v uint16S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v uint16S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v uint16S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v uint16S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v uint16S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Uint16(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Uint32Slice returns stringer/JSON marshaler interface implementation for the uint32 slice type.
+func Uint32Slice(v []uint32) uint32S { return uint32S{V: v} }
+
+type uint32S struct{ V []uint32 }
+
+func (v uint32S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v uint32S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v uint32S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v uint32S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v uint32S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Uint32(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Uint64Slice returns stringer/JSON marshaler interface implementation for the uint64 slice type.
+func Uint64Slice(v []uint64) uint64S { return uint64S{V: v} }
+
+type uint64S struct{ V []uint64 }
+
+func (v uint64S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v uint64S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v uint64S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v uint64S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v uint64S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Uint64(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Uint8Slice returns stringer/JSON marshaler interface implementation for the uint8 slice type.
+func Uint8Slice(v []uint8) uint8S { return uint8S{V: v} }
+
+type uint8S struct{ V []uint8 }
+
+func (v uint8S) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v uint8S) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v uint8S) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v uint8S) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v uint8S) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Uint8(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// UintptrSlice returns stringer/JSON marshaler interface implementation for the uintptr slice type.
+func UintptrSlice(v []uintptr) uintptrS { return uintptrS{V: v} }
+
+type uintptrS struct{ V []uintptr }
+
+func (v uintptrS) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v uintptrS) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v uintptrS) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v uintptrS) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v uintptrS) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		dst, err = Uintptr(x).AppendJSON(dst)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
+}
+
+// Array returns stringer/JSON marshaler interface implementation for the JSON array of the values.
+func Array(v ...json.Marshaler) arrayV { return arrayV{V: v} }
+
+type arrayV struct{ V []json.Marshaler }
+
+func (v arrayV) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v arrayV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v arrayV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v arrayV) AppendText(dst []byte) ([]byte, error) {
+	return v.AppendJSON(dst)
+}
+
+func (v arrayV) AppendJSON(dst []byte) ([]byte, error) {
+	var err error
+
+	dst = append(dst, '[')
+
+	for i, x := range v.V {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+
+		if x == nil {
+			dst = append(dst, "null"...)
+			continue
+		}
+
+		dst, err = appendJSON(dst, x)
+		if err != nil {
+			return dst, err
+		}
+	}
+
+	return append(dst, ']'), nil
 }
@@ -2225,6 +2225,24 @@ var MarshalTestCases = []struct {
 			"reflect untyped nil":null
 		}`,
 	},
+	{
+		line:         line(),
+		input:        map[string]json.Marshaler{"object": log0.Object(log0.Strings("foo", "bar"), log0.StringInt("baz", 42))},
+		expected:     `{"foo":"bar","baz":42}`,
+		expectedText: `{"foo":"bar","baz":42}`,
+		expectedJSON: `{
+			"object":{"foo":"bar","baz":42}
+		}`,
+	},
+	{
+		line:         line(),
+		input:        map[string]json.Marshaler{"empty object": log0.Object()},
+		expected:     `{}`,
+		expectedText: `{}`,
+		expectedJSON: `{
+			"empty object":{}
+		}`,
+	},
 }
 
 func TestMarshal(t *testing.T) {
@@ -2277,6 +2295,51 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalAppend(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range MarshalTestCases {
+		tc := tc
+		t.Run(fmt.Sprint(tc.input), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			for k, v := range tc.input {
+				js, ok := v.(log0.JSONAppender)
+				if !ok {
+					continue
+				}
+
+				txt, ok := v.(log0.TextAppender)
+				if !ok {
+					t.Errorf("%q does not implement the text appender interface", k)
+
+				} else {
+					p, err := txt.AppendText([]byte("prefix"))
+					if err != nil {
+						t.Fatalf("%q append text error: %s %s", k, err, linkToExample)
+					}
+
+					if string(p) != "prefix"+tc.expectedText {
+						t.Errorf("%q unexpected text, expected: %q, recieved: %q %s", k, "prefix"+tc.expectedText, string(p), linkToExample)
+					}
+				}
+
+				p, err := js.AppendJSON([]byte("prefix"))
+
+				j, err0 := v.MarshalJSON()
+
+				if !equal4.ErrorEqual(err, err0) {
+					t.Fatalf("%q append JSON error expected: %s, recieved: %s %s", k, err0, err, linkToExample)
+				}
+
+				if err == nil && string(p) != "prefix"+string(j) {
+					t.Errorf("%q unexpected JSON, expected: %q, recieved: %q %s", k, "prefix"+string(j), string(p), linkToExample)
+				}
+			}
+		})
+	}
+}
+
 // type testprinter struct {
 // 	t    *testing.T
 // 	link string
@@ -0,0 +1,327 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rotate0 implements rotating file writer.
+package rotate0
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danil/log0"
+)
+
+// TimeFormat is a time format of the rotated file names.
+const TimeFormat = "2006-01-02T15-04-05.000"
+
+// Writer is a file writer which rotates the file by size and/or by time interval.
+// Rotated "/var/log/app.log" is named "/var/log/app-2006-01-02T15-04-05.000.log"
+// by UTC time of the rotation (with "-1", "-2", etc. suffix if the name is taken)
+// plus ".gz" extension if compressed.
+type Writer struct {
+	Filename   string        // Filename is a path of the file.
+	Perm       os.FileMode   // Perm is a permission bits of the created files, 0644 if zero.
+	MaxSize    int64         // MaxSize is a maximum size of the file in bytes, file is not rotated by size if zero.
+	Interval   time.Duration // Interval is a rotation interval aligned to the zero time, file is not rotated by time if zero.
+	MaxBackups int           // MaxBackups is a maximum number of the rotated files, all files are kept if zero.
+	MaxAge     time.Duration // MaxAge is a maximum age of the rotated files, all files are kept if zero.
+	Compress   bool          // Compress compresses rotated files by gzip in background.
+	Clock      log0.Clock    // Clock is a source of the rotation time, system clock is used if nil.
+
+	mu   sync.Mutex
+	file *os.File
+	size int64     // size is a size of the file.
+	next time.Time // next is a time of the next rotation by time.
+
+	bg sync.Mutex     // bg serializes background compression and removal.
+	wg sync.WaitGroup // wg waits background compression and removal.
+}
+
+func (w *Writer) now() time.Time {
+	if w.Clock == nil {
+		return time.Now()
+	}
+	return w.Clock.Now()
+}
+
+func (w *Writer) perm() os.FileMode {
+	if w.Perm == 0 {
+		return 0644
+	}
+	return w.Perm
+}
+
+// Write writes entry to the file, the file is rotated before the write
+// if the entry exceeds maximum size or rotation interval expired.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+
+	if w.file == nil {
+		err := w.open(now)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if w.Interval > 0 && !now.Before(w.next) ||
+		w.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.MaxSize {
+		err := w.rotate(now)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// open opens or creates the file.
+func (w *Writer) open(now time.Time) error {
+	err := os.MkdirAll(filepath.Dir(w.Filename), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(w.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.perm())
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	w.file = f
+	w.size = info.Size()
+
+	if w.Interval > 0 {
+		w.next = now.Truncate(w.Interval).Add(w.Interval)
+	}
+
+	return nil
+}
+
+// rotate renames the file, opens the new file and starts background
+// compression and removal of the rotated files.
+func (w *Writer) rotate(now time.Time) error {
+	err := w.file.Close()
+	w.file = nil
+	if err != nil {
+		return err
+	}
+
+	name := w.backupName(now)
+
+	err = os.Rename(w.Filename, name)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = w.open(now)
+	if err != nil {
+		return err
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.bg.Lock()
+		defer w.bg.Unlock()
+		if w.Compress {
+			_ = compress(name, w.perm())
+		}
+		w.remove(now)
+	}()
+
+	return nil
+}
+
+// split returns prefix and extension of the rotated file names.
+func (w *Writer) split() (string, string) {
+	ext := filepath.Ext(w.Filename)
+	return strings.TrimSuffix(w.Filename, ext) + "-", ext
+}
+
+// backupName returns not taken name of the rotated file.
+func (w *Writer) backupName(now time.Time) string {
+	prefix, ext := w.split()
+	stamp := prefix + now.UTC().Format(TimeFormat)
+
+	name := stamp + ext
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = stamp + "-" + strconv.Itoa(i) + ext
+	}
+
+	return name
+}
+
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// compress compresses the file by gzip and removes the original file.
+func compress(name string, perm os.FileMode) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if e := dst.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+
+	return os.Remove(name)
+}
+
+// backup is a rotated file.
+type backup struct {
+	name string
+	t    time.Time
+}
+
+// remove removes rotated files which exceed the maximum number
+// or the maximum age.
+func (w *Writer) remove(now time.Time) {
+	if w.MaxBackups <= 0 && w.MaxAge <= 0 {
+		return
+	}
+
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+
+	for i, b := range backups {
+		if w.MaxBackups > 0 && i >= w.MaxBackups || w.MaxAge > 0 && now.Sub(b.t) > w.MaxAge {
+			_ = os.Remove(b.name)
+		}
+	}
+}
+
+// backups returns rotated files from the newest to the oldest.
+func (w *Writer) backups() ([]backup, error) {
+	prefix, ext := w.split()
+
+	entries, err := os.ReadDir(filepath.Dir(w.Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	base := filepath.Base(prefix)
+
+	var backups []backup
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, base) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base), ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ext)
+
+		if len(stamp) < len(TimeFormat) {
+			continue
+		}
+
+		t, err := time.Parse(TimeFormat, stamp[:len(TimeFormat)])
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, backup{name: filepath.Join(filepath.Dir(w.Filename), name), t: t})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		if backups[i].t.Equal(backups[j].t) {
+			return backups[i].name > backups[j].name
+		}
+		return backups[i].t.After(backups[j].t)
+	})
+
+	return backups, nil
+}
+
+// Reopen closes and reopens the file,
+// for example after the file is moved by the external tool.
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file != nil {
+		err := w.file.Close()
+		w.file = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.open(w.now())
+}
+
+// Rotate rotates the file.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+
+	if w.file == nil {
+		err := w.open(now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.rotate(now)
+}
+
+// Close closes the file and waits background compression and removal.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+
+	return err
+}
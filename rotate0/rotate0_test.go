@@ -0,0 +1,271 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rotate0_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/danil/log0/rotate0"
+)
+
+type clock struct{ t *time.Time }
+
+func (c clock) Now() time.Time { return *c.t }
+
+// files returns names and contents of the files of the directory.
+func files(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected read dir error: %s", err)
+	}
+
+	m := make(map[string]string)
+
+	for _, e := range entries {
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("unexpected open error: %s", err)
+		}
+
+		var r io.Reader = f
+		if filepath.Ext(e.Name()) == ".gz" {
+			r, err = gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("unexpected gzip error: %s", err)
+			}
+		}
+
+		p, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("unexpected read error: %s", err)
+		}
+
+		f.Close()
+
+		m[e.Name()] = string(p)
+	}
+
+	return m
+}
+
+func names(m map[string]string) []string {
+	var s []string
+	for k := range m {
+		s = append(s, k)
+	}
+	sort.Strings(s)
+	return s
+}
+
+func write(t *testing.T, w io.Writer, s ...string) {
+	t.Helper()
+	for _, p := range s {
+		_, err := w.Write([]byte(p))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+}
+
+func TestRotateSize(t *testing.T) {
+	dir := t.TempDir()
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	w := &rotate0.Writer{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxSize:  8,
+		Clock:    clock{t: &now},
+	}
+
+	write(t, w, "foo\n", "bar\n", "baz\n")
+
+	now = now.Add(time.Second)
+
+	write(t, w, "xyz\n", "qux\n")
+
+	err := w.Close()
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	m := files(t, dir)
+
+	expected := map[string]string{
+		"app.log":                         "qux\n",
+		"app-2020-10-15T18-09-00.000.log": "foo\nbar\n",
+		"app-2020-10-15T18-09-01.000.log": "baz\nxyz\n",
+	}
+
+	if len(m) != len(expected) {
+		t.Fatalf("unexpected files: %q", names(m))
+	}
+
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("unexpected file %s content, expected: %q, received: %q", k, v, m[k])
+		}
+	}
+}
+
+func TestRotateTimeCompressBackups(t *testing.T) {
+	dir := t.TempDir()
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	w := &rotate0.Writer{
+		Filename:   filepath.Join(dir, "app.log"),
+		Interval:   time.Hour,
+		MaxBackups: 2,
+		Compress:   true,
+		Clock:      clock{t: &now},
+	}
+
+	for _, s := range []string{"foo\n", "bar\n", "baz\n", "xyz\n"} {
+		write(t, w, s)
+		now = now.Add(time.Hour)
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	m := files(t, dir)
+
+	expected := map[string]string{
+		"app.log":                            "xyz\n",
+		"app-2020-10-15T20-09-00.000.log.gz": "bar\n",
+		"app-2020-10-15T21-09-00.000.log.gz": "baz\n",
+	}
+
+	if len(m) != len(expected) {
+		t.Fatalf("unexpected files: %q", names(m))
+	}
+
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("unexpected file %s content, expected: %q, received: %q", k, v, m[k])
+		}
+	}
+}
+
+func TestRotateMaxAge(t *testing.T) {
+	dir := t.TempDir()
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	w := &rotate0.Writer{
+		Filename: filepath.Join(dir, "app"),
+		MaxAge:   90 * time.Minute,
+		Clock:    clock{t: &now},
+	}
+
+	for i := 0; i < 3; i++ {
+		write(t, w, "foo\n")
+		err := w.Rotate()
+		if err != nil {
+			t.Fatalf("unexpected rotate error: %s", err)
+		}
+		now = now.Add(time.Hour)
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	m := files(t, dir)
+
+	expected := []string{"app", "app-2020-10-15T19-09-00.000", "app-2020-10-15T20-09-00.000"}
+
+	if len(m) != len(expected) {
+		t.Fatalf("unexpected files: %q", names(m))
+	}
+
+	for _, k := range expected {
+		if _, ok := m[k]; !ok {
+			t.Errorf("expected file %s, received: %q", k, names(m))
+		}
+	}
+}
+
+func TestRotateNameCollision(t *testing.T) {
+	dir := t.TempDir()
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	w := &rotate0.Writer{
+		Filename: filepath.Join(dir, "app.log"),
+		Clock:    clock{t: &now},
+	}
+
+	for i := 0; i < 3; i++ {
+		write(t, w, "foo\n")
+		err := w.Rotate()
+		if err != nil {
+			t.Fatalf("unexpected rotate error: %s", err)
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	expected := []string{
+		"app-2020-10-15T18-09-00.000-1.log",
+		"app-2020-10-15T18-09-00.000-2.log",
+		"app-2020-10-15T18-09-00.000.log",
+		"app.log",
+	}
+
+	if received := names(files(t, dir)); len(received) != len(expected) || received[0] != expected[0] || received[1] != expected[1] || received[2] != expected[2] || received[3] != expected[3] {
+		t.Errorf("unexpected files, expected: %q, received: %q", expected, received)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "app.log")
+
+	w := &rotate0.Writer{Filename: name}
+
+	write(t, w, "foo\n")
+
+	err := os.Rename(name, name+".1")
+	if err != nil {
+		t.Fatalf("unexpected rename error: %s", err)
+	}
+
+	write(t, w, "bar\n")
+
+	err = w.Reopen()
+	if err != nil {
+		t.Fatalf("unexpected reopen error: %s", err)
+	}
+
+	write(t, w, "baz\n")
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	m := files(t, dir)
+
+	if m["app.log.1"] != "foo\nbar\n" || m["app.log"] != "baz\n" {
+		t.Errorf("unexpected files: %q", m)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package rotate0
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifySIGHUP reopens the file on each SIGHUP signal
+// until the returned stop function is called.
+func (w *Writer) NotifySIGHUP() (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(c, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				_ = w.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
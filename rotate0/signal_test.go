@@ -0,0 +1,55 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package rotate0_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/danil/log0/rotate0"
+)
+
+func TestNotifySIGHUP(t *testing.T) {
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "app.log")
+
+	w := &rotate0.Writer{Filename: name}
+	defer w.Close()
+
+	stop := w.NotifySIGHUP()
+	defer stop()
+
+	write(t, w, "foo\n")
+
+	err := os.Rename(name, name+".1")
+	if err != nil {
+		t.Fatalf("unexpected rename error: %s", err)
+	}
+
+	err = syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	if err != nil {
+		t.Fatalf("unexpected kill error: %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		_, err = os.Stat(name)
+		if err == nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err != nil {
+		t.Fatalf("file is not reopened: %s", err)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rotate0
+
+// NotifySIGHUP does nothing because Windows has no SIGHUP signal.
+func (w *Writer) NotifySIGHUP() (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0
+
+import (
+	"encoding"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sampleBuckets is a number of the sampling counters,
+// messages with the same hash share the counter.
+const sampleBuckets = 4096
+
+// Sampler samples entries by the message and the severity level.
+// Entries are counted per message and severity level over the interval:
+// first entries of the interval are written and thereafter every Mth entry is written.
+// If both First and Thereafter are zero then entries are written
+// with the probability.
+// Sampler is safe for concurrent use by the loggers obtained by the Get.
+type Sampler struct {
+	First       uint64                                  // First is a number of the entries per interval which are written.
+	Thereafter  uint64                                  // Thereafter: every Mth entry after the first entries is written, none if zero.
+	Interval    time.Duration                           // Interval is a sampling interval, one second if zero.
+	Probability float64                                 // Probability is a probability of the writing of the entry if First and Thereafter are zero.
+	Level       Level                                   // Level is a most severe level which is sampled, all entries are sampled if zero.
+	Key         encoding.TextMarshaler                  // Key is a key of the number of the dropped entries written on the next written entry of the same message and severity level, not written if nil.
+	Dropped     func(level Level, msg []byte, n uint64) // Dropped function receives severity level, message and number of the dropped entries since the last written entry on each dropped entry.
+	Clock       Clock                                   // Clock is a source of the interval time, system clock is used if nil.
+	Rand        func() float64                          // Rand returns pseudo-random number in [0.0,1.0), math/rand is used if nil.
+
+	mu      sync.Mutex
+	buckets [sampleBuckets]sampleBucket
+}
+
+// sampleBucket is a counter of the entries of the interval.
+type sampleBucket struct {
+	reset   int64  // reset is a unix nanoseconds of the end of the interval.
+	n       uint64 // n is a number of the entries of the interval.
+	dropped uint64 // dropped is a number of the dropped entries since the last written entry.
+}
+
+// sample reports whether the entry is written and returns number of the dropped entries
+// since the last written entry of the same message and severity level.
+func (s *Sampler) sample(level Level, msg []byte) (bool, uint64) {
+	if s.Level != 0 && level.Valid() && level < s.Level {
+		return true, 0
+	}
+
+	var now time.Time
+	if s.Clock == nil {
+		now = time.Now()
+	} else {
+		now = s.Clock.Now()
+	}
+
+	interval := s.Interval
+	if interval == 0 {
+		interval = time.Second
+	}
+
+	// FNV-1a hash of the message and the severity level.
+	h := uint64(14695981039346656037)
+	for _, c := range msg {
+		h = (h ^ uint64(c)) * 1099511628211
+	}
+	h = (h ^ uint64(level)) * 1099511628211
+
+	s.mu.Lock()
+
+	b := &s.buckets[h%sampleBuckets]
+
+	if ns := now.UnixNano(); ns >= b.reset {
+		b.reset = ns + int64(interval)
+		b.n = 0
+	}
+
+	b.n++
+
+	var ok bool
+	if s.First != 0 || s.Thereafter != 0 {
+		ok = b.n <= s.First || s.Thereafter != 0 && (b.n-s.First)%s.Thereafter == 0
+	} else if s.Rand == nil {
+		ok = rand.Float64() < s.Probability
+	} else {
+		ok = s.Rand() < s.Probability
+	}
+
+	var n uint64
+	if ok {
+		n, b.dropped = b.dropped, 0
+	} else {
+		b.dropped++
+		n = b.dropped
+	}
+
+	s.mu.Unlock()
+
+	if !ok && s.Dropped != nil {
+		s.Dropped(level, msg, n)
+	}
+
+	return ok, n
+}
+
+// dropped is a number of the dropped entries key-value pair.
+type dropped struct {
+	K encoding.TextMarshaler
+	N uint64
+}
+
+func (kv *dropped) MarshalText() ([]byte, error) { return kv.K.MarshalText() }
+
+func (kv *dropped) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+
+func (kv *dropped) MarshalJSON() ([]byte, error) { return kv.AppendJSON(nil) }
+
+func (kv *dropped) AppendJSON(dst []byte) ([]byte, error) {
+	return strconv.AppendUint(dst, kv.N, 10), nil
+}
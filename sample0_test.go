@@ -0,0 +1,209 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0_test
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/kinbiko/jsonassert"
+)
+
+type tickClock struct{ t *time.Time }
+
+func (c tickClock) Now() time.Time { return *c.t }
+
+func TestSamplerFirstThereafter(t *testing.T) {
+	var buf bytes.Buffer
+
+	now := time.Date(2020, time.October, 15, 18, 9, 0, 0, time.UTC)
+
+	var drops []string
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Sampler: &log0.Sampler{
+			First:      2,
+			Thereafter: 3,
+			Interval:   time.Minute,
+			Key:        log0.String("dropped"),
+			Clock:      tickClock{t: &now},
+			Dropped: func(level log0.Level, msg []byte, n uint64) {
+				drops = append(drops, fmt.Sprintf("%s %s %d", level, msg, n))
+			},
+		},
+	}
+
+	for i := 0; i < 7; i++ {
+		err := l.Info("foo")
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	err := l.Debug("foo")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	now = now.Add(time.Minute)
+
+	err = l.Info("foo")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	expected := []string{
+		`{"message":"foo","severity":"6"}`,
+		`{"message":"foo","severity":"6"}`,
+		`{"dropped":2,"message":"foo","severity":"6"}`,
+		`{"message":"foo","severity":"7"}`,
+		`{"dropped":2,"message":"foo","severity":"6"}`,
+	}
+
+	if buf.String() != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("unexpected output, expected:\n%s\nreceived:\n%s", strings.Join(expected, "\n"), buf.String())
+	}
+
+	expectedDrops := []string{"info foo 1", "info foo 2", "info foo 1", "info foo 2"}
+
+	if fmt.Sprint(drops) != fmt.Sprint(expectedDrops) {
+		t.Errorf("unexpected drops, expected: %q, received: %q", expectedDrops, drops)
+	}
+}
+
+func TestSamplerLevel(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:  &buf,
+		Keys:    [4]encoding.TextMarshaler{log0.String("message")},
+		Sampler: &log0.Sampler{First: 1, Level: log0.LevelWarning},
+	}
+
+	for i := 0; i < 3; i++ {
+		err := l.Error("foo")
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+
+		err = l.Warning("bar")
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+
+		_, err = l.Get(log0.StringSeverity("severity", "3")).Write([]byte("xyz"))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	if n := strings.Count(buf.String(), `"foo"`); n != 3 {
+		t.Errorf("unexpected number of the not sampled entries: %d", n)
+	}
+
+	if n := strings.Count(buf.String(), `"bar"`); n != 1 {
+		t.Errorf("unexpected number of the sampled entries: %d", n)
+	}
+
+	if n := strings.Count(buf.String(), `"xyz"`); n != 3 {
+		t.Errorf("unexpected number of the not sampled entries of the get: %d", n)
+	}
+}
+
+func TestSamplerProbability(t *testing.T) {
+	var buf bytes.Buffer
+
+	rnd := []float64{0.1, 0.5, 0.9, 0.2}
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Sampler: &log0.Sampler{
+			Probability: 0.3,
+			Key:         log0.String("dropped"),
+			Rand: func() float64 {
+				r := rnd[0]
+				rnd = rnd[1:]
+				return r
+			},
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		_, err := l.Write([]byte("foo"))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	ja := jsonassert.New(t)
+	ja.Assertf(strings.Split(buf.String(), "\n")[0], `{"message":"foo"}`)
+	ja.Assertf(strings.Split(buf.String(), "\n")[1], `{"message":"foo","dropped":2}`)
+}
+
+func TestSamplerConcurrent(t *testing.T) {
+	var mu sync.Mutex
+
+	var n int
+
+	l := &log0.Log{
+		Output: writerFunc(func(p []byte) (int, error) {
+			mu.Lock()
+			n++
+			mu.Unlock()
+			return len(p), nil
+		}),
+		Keys:    [4]encoding.TextMarshaler{log0.String("message")},
+		Sampler: &log0.Sampler{First: 10, Interval: time.Hour},
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l1 := l.Get(log0.StringInt("foo", 42))
+			defer l1.Put()
+			for j := 0; j < 100; j++ {
+				_, _ = l1.Write([]byte("bar"))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n != 10 {
+		t.Errorf("unexpected number of the written entries: %d", n)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func BenchmarkSampler(b *testing.B) {
+	l := &log0.Log{
+		Output:  io.Discard,
+		Keys:    [4]encoding.TextMarshaler{log0.String("message")},
+		Sampler: &log0.Sampler{First: 10, Thereafter: 100, Key: log0.String("dropped")},
+	}
+	p := []byte("Hello, World!")
+	for i := 0; i < b.N; i++ {
+		_, err := l.Write(p)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+// Package slog0 implements log/slog handler backed by the log0 logger.
+package slog0
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/danil/log0"
+)
+
+// NewHandler returns slog handler which writes records through the logger.
+// Record attributes are written as a key-values of the leveled methods
+// of the logger. If the logger is a log0.Log with the timestamp key
+// then the record time is written as its timestamp,
+// otherwise the record time is written with the "time" key if not zero.
+// Caller of the log0.Log is a program counter of the record.
+func NewHandler(l log0.Logger) slog.Handler {
+	return &handler{l: l}
+}
+
+type handler struct {
+	l      log0.Logger // l is a logger with key-values of the attributes outside of the groups.
+	groups []group     // groups is an open groups of the attributes.
+}
+
+// clock is a clock of the record time.
+type clock time.Time
+
+func (c clock) Now() time.Time { return time.Time(c) }
+
+// group is a named group of the attributes.
+type group struct {
+	name string
+	kv   []log0.KV
+}
+
+// Level returns severity level of the slog level:
+// less than info = debug; info = info; greater than info = notice;
+// warn = warning; error = error; error+4 = critical; error+8 = alert; error+12 and greater = emergency.
+func Level(l slog.Level) log0.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return log0.LevelDebug
+	case l == slog.LevelInfo:
+		return log0.LevelInfo
+	case l < slog.LevelWarn:
+		return log0.LevelNotice
+	case l < slog.LevelError:
+		return log0.LevelWarning
+	case l < slog.LevelError+4:
+		return log0.LevelError
+	case l < slog.LevelError+8:
+		return log0.LevelCritical
+	case l < slog.LevelError+12:
+		return log0.LevelAlert
+	}
+	return log0.LevelEmergency
+}
+
+func (h *handler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.l.Enabled(Level(l))
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l := h.l.Ctx(ctx)
+	defer l.Put()
+
+	timed := false
+
+	if l0, ok := l.(*log0.Log); ok {
+		if r.PC == 0 {
+			l0.Caller = 0
+		} else {
+			l0.CallerPC = r.PC
+		}
+
+		if l0.Time != nil && !r.Time.IsZero() {
+			l0.Clock = clock(r.Time)
+			timed = true
+		}
+	}
+
+	kv := make([]log0.KV, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		kv = appendAttr(kv, a)
+		return true
+	})
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		kv = append(h.groups[i].kv[:len(h.groups[i].kv):len(h.groups[i].kv)], kv...)
+		if len(kv) == 0 {
+			continue
+		}
+		kv = []log0.KV{log0.StringObject(h.groups[i].name, kv...)}
+	}
+
+	if !timed && !r.Time.IsZero() {
+		kv = append([]log0.KV{log0.StringTime(slog.TimeKey, r.Time)}, kv...)
+	}
+
+	switch Level(r.Level) {
+	case log0.LevelEmergency:
+		return l.Emergency(r.Message, kv...)
+	case log0.LevelAlert:
+		return l.Alert(r.Message, kv...)
+	case log0.LevelCritical:
+		return l.Critical(r.Message, kv...)
+	case log0.LevelError:
+		return l.Error(r.Message, kv...)
+	case log0.LevelWarning:
+		return l.Warning(r.Message, kv...)
+	case log0.LevelNotice:
+		return l.Notice(r.Message, kv...)
+	case log0.LevelInfo:
+		return l.Info(r.Message, kv...)
+	}
+	return l.Debug(r.Message, kv...)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var kv []log0.KV
+	for _, a := range attrs {
+		kv = appendAttr(kv, a)
+	}
+
+	if len(kv) == 0 {
+		return h
+	}
+
+	if len(h.groups) == 0 {
+		return &handler{l: h.l.Get(kv...)}
+	}
+
+	groups := append([]group(nil), h.groups...)
+	last := &groups[len(groups)-1]
+	last.kv = append(append([]log0.KV(nil), last.kv...), kv...)
+
+	return &handler{l: h.l, groups: groups}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]group, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+
+	return &handler{l: h.l, groups: append(groups, group{name: name})}
+}
+
+// appendAttr appends key-value of the attribute.
+// Empty attributes are ignored and attributes of the group
+// with the empty key are inlined.
+func appendAttr(kv []log0.KV, a slog.Attr) []log0.KV {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return kv
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		var attrs []log0.KV
+		for _, ga := range a.Value.Group() {
+			attrs = appendAttr(attrs, ga)
+		}
+		if len(attrs) == 0 {
+			return kv
+		}
+		if a.Key == "" {
+			return append(kv, attrs...)
+		}
+		return append(kv, log0.StringObject(a.Key, attrs...))
+
+	case slog.KindString:
+		return append(kv, log0.Strings(a.Key, a.Value.String()))
+
+	case slog.KindInt64:
+		return append(kv, log0.StringInt64(a.Key, a.Value.Int64()))
+
+	case slog.KindUint64:
+		return append(kv, log0.StringUint64(a.Key, a.Value.Uint64()))
+
+	case slog.KindFloat64:
+		return append(kv, log0.StringFloat64(a.Key, a.Value.Float64()))
+
+	case slog.KindBool:
+		return append(kv, log0.StringBool(a.Key, a.Value.Bool()))
+
+	case slog.KindDuration:
+		return append(kv, log0.StringDuration(a.Key, a.Value.Duration()))
+
+	case slog.KindTime:
+		return append(kv, log0.StringTime(a.Key, a.Value.Time()))
+	}
+
+	v := a.Value.Any()
+	if err, ok := v.(error); ok {
+		return append(kv, log0.StringError(a.Key, err))
+	}
+
+	return append(kv, log0.StringAny(a.Key, v))
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package slog0_test
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/slog0"
+	"github.com/kinbiko/jsonassert"
+)
+
+func TestSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:      &buf,
+		Keys:        [4]encoding.TextMarshaler{log0.String(slog.MessageKey)},
+		SeverityKey: log0.String(slog.LevelKey),
+		Order:       log0.Insertion,
+	}
+
+	results := func() []map[string]interface{} {
+		var ms []map[string]interface{}
+		for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
+			if len(line) == 0 {
+				continue
+			}
+			var m map[string]interface{}
+			err := json.Unmarshal(line, &m)
+			if err != nil {
+				t.Fatalf("unexpected unmarshal error: %s, json: %s", err, line)
+			}
+			ms = append(ms, m)
+		}
+		return ms
+	}
+
+	err := slogtest.TestHandler(slog0.NewHandler(l), results)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:         &buf,
+		Keys:           [4]encoding.TextMarshaler{log0.String("message")},
+		SeverityFormat: log0.SeverityName,
+		Threshold:      log0.LevelInfo,
+	}
+
+	logger := slog.New(slog0.NewHandler(l))
+
+	logger.Debug("foo")
+
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output below the threshold: %s", buf.String())
+	}
+
+	logger.With("service", "api").
+		WithGroup("request").
+		With("method", "GET").
+		Warn("Hello, World!",
+			"duration", time.Second,
+			"err", errors.New("bar"),
+			slog.Group("user", "id", 42, "admin", true),
+		)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buf.String(), `{
+		"message":"Hello, World!",
+		"severity":"warning",
+		"time":"<<PRESENCE>>",
+		"service":"api",
+		"request":{
+			"method":"GET",
+			"duration":"1s",
+			"err":"bar",
+			"user":{"id":42,"admin":true}
+		}
+	}`)
+}
+
+func TestHandlerCallerAndTime(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:     &buf,
+		Keys:       [4]encoding.TextMarshaler{log0.String("message")},
+		Time:       log0.String("ts"),
+		TimeFormat: log0.Unix,
+		Caller:     log0.CallerFields,
+	}
+
+	logger := slog.New(slog0.NewHandler(l))
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("foo")
+
+	var m map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &m)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %s, json: %s", err, buf.String())
+	}
+
+	if m["file"] != file || m["line"] != float64(line+1) {
+		t.Errorf("unexpected caller, expected: %s:%d, received: %v:%v", file, line+1, m["file"], m["line"])
+	}
+
+	if _, ok := m["time"]; ok || m["ts"] == nil {
+		t.Errorf("unexpected timestamps: %s", buf.String())
+	}
+}
+
+// infof is a logging helper which reports the caller of the helper.
+func infof(h slog.Handler, format string, args ...interface{}) error {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, fmt.Sprintf(format, args...), pcs[0])
+	return h.Handle(context.Background(), r)
+}
+
+func TestHandlerRecordPC(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Caller: log0.CallerFields,
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	err := infof(slog0.NewHandler(l), "foo %d", 42)
+	if err != nil {
+		t.Fatalf("unexpected handle error: %s", err)
+	}
+
+	var m map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &m)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %s, json: %s", err, buf.String())
+	}
+
+	if m["message"] != "foo 42" || m["file"] != file || m["line"] != float64(line+1) {
+		t.Errorf("unexpected entry, expected caller: %s:%d, received: %s", file, line+1, buf.String())
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for l, expected := range map[slog.Level]log0.Level{
+		slog.LevelDebug - 4:  log0.LevelDebug,
+		slog.LevelDebug:      log0.LevelDebug,
+		slog.LevelInfo:       log0.LevelInfo,
+		slog.LevelInfo + 2:   log0.LevelNotice,
+		slog.LevelWarn:       log0.LevelWarning,
+		slog.LevelError:      log0.LevelError,
+		slog.LevelError + 4:  log0.LevelCritical,
+		slog.LevelError + 8:  log0.LevelAlert,
+		slog.LevelError + 12: log0.LevelEmergency,
+	} {
+		if Level := slog0.Level(l); Level != expected {
+			t.Errorf("unexpected level of the %s, expected: %s, received: %s", l, expected, Level)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0
+
+import (
+	"encoding"
+	"runtime"
+	"strconv"
+
+	"github.com/danil/log0/encode0"
+)
+
+// Stack trace formats.
+const (
+	StackFrames = iota // StackFrames is an array of the frames: [{"function":"main.main","file":"/path/to/main.go","line":42}].
+	StackText          // StackText is a single string of the frames: "main.main\n\t/path/to/main.go:42\n".
+)
+
+// stackDepth is a maximum number of the stack frames.
+const stackDepth = 64
+
+// ErrorStack returns error value which records stack trace of the caller
+// of the ErrorStack and which is marshaled to JSON object:
+// {"error":"message","stack":[{"function":"main.main","file":"/path/to/main.go","line":42}]}.
+func ErrorStack(v error) errorStackV {
+	return errorStackV{V: v, S: callers(StackFrames)}
+}
+
+// ErrorStackText is the same as the ErrorStack except that
+// stack trace is marshaled to the single string.
+func ErrorStackText(v error) errorStackV {
+	return errorStackV{V: v, S: callers(StackText)}
+}
+
+// callers returns stack trace of the caller of the ErrorStack/ErrorStackText.
+func callers(format uint8) stackV {
+	var pc [stackDepth]uintptr
+	n := runtime.Callers(3, pc[:])
+	return stackV{PC: append([]uintptr(nil), pc[:n]...), F: format}
+}
+
+type errorStackV struct {
+	V error
+	S stackV
+}
+
+func (v errorStackV) String() string {
+	p, _ := v.MarshalText()
+	return string(p)
+}
+
+func (v errorStackV) MarshalText() ([]byte, error) {
+	return v.AppendText(nil)
+}
+
+func (v errorStackV) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+func (v errorStackV) AppendText(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+	dst = append(dst, v.V.Error()...)
+	dst = append(dst, '\n')
+	return v.S.appendText(dst), nil
+}
+
+func (v errorStackV) AppendJSON(dst []byte) ([]byte, error) {
+	if v.V == nil {
+		return append(dst, "null"...), nil
+	}
+	dst = append(dst, `{"error":"`...)
+	dst = encode0.AppendString(dst, v.V.Error())
+	dst = append(dst, `","stack":`...)
+	dst = v.S.appendJSON(dst)
+	return append(dst, '}'), nil
+}
+
+// stackV is a stack trace.
+type stackV struct {
+	PC []uintptr // PC is a program counters of the stack frames.
+	F  uint8     // F is a stack trace format.
+}
+
+func (v stackV) appendText(dst []byte) []byte {
+	frames := runtime.CallersFrames(v.PC)
+	for {
+		f, more := frames.Next()
+		if f.PC != 0 || f.Function != "" {
+			dst = append(dst, f.Function...)
+			dst = append(dst, "\n\t"...)
+			dst = append(dst, f.File...)
+			dst = append(dst, ':')
+			dst = strconv.AppendInt(dst, int64(f.Line), 10)
+			dst = append(dst, '\n')
+		}
+		if !more {
+			return dst
+		}
+	}
+}
+
+func (v stackV) appendJSON(dst []byte) []byte {
+	if v.F == StackText {
+		dst = append(dst, '"')
+		frames := runtime.CallersFrames(v.PC)
+		for {
+			f, more := frames.Next()
+			if f.PC != 0 || f.Function != "" {
+				dst = encode0.AppendString(dst, f.Function)
+				dst = append(dst, `\n\t`...)
+				dst = encode0.AppendString(dst, f.File)
+				dst = append(dst, ':')
+				dst = strconv.AppendInt(dst, int64(f.Line), 10)
+				dst = append(dst, `\n`...)
+			}
+			if !more {
+				return append(dst, '"')
+			}
+		}
+	}
+
+	dst = append(dst, '[')
+	frames := runtime.CallersFrames(v.PC)
+	first := true
+	for {
+		f, more := frames.Next()
+		if f.PC != 0 || f.Function != "" {
+			if !first {
+				dst = append(dst, ',')
+			}
+			first = false
+			dst = append(dst, `{"function":"`...)
+			dst = encode0.AppendString(dst, f.Function)
+			dst = append(dst, `","file":"`...)
+			dst = encode0.AppendString(dst, f.File)
+			dst = append(dst, `","line":`...)
+			dst = strconv.AppendInt(dst, int64(f.Line), 10)
+			dst = append(dst, '}')
+		}
+		if !more {
+			return append(dst, ']')
+		}
+	}
+}
+
+// stack is a stack trace key-value pair of the log entry.
+type stack struct {
+	K encoding.TextMarshaler
+	V stackV
+}
+
+func (kv *stack) MarshalText() ([]byte, error) { return kv.K.MarshalText() }
+
+func (kv *stack) AppendText(dst []byte) ([]byte, error) { return appendText(dst, kv.K) }
+
+func (kv *stack) MarshalJSON() ([]byte, error) { return kv.AppendJSON(nil) }
+
+func (kv *stack) AppendJSON(dst []byte) ([]byte, error) { return kv.V.appendJSON(dst), nil }
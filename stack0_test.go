@@ -0,0 +1,173 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log0_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/danil/log0"
+)
+
+type frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func TestErrorStack(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	p, err := json.Marshal(log0.ErrorStack(errors.New("foo")))
+	errorLine := line() - 1
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %s", err)
+	}
+
+	var v struct {
+		Error string  `json:"error"`
+		Stack []frame `json:"stack"`
+	}
+
+	err = json.Unmarshal(p, &v)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %s, json: %s", err, p)
+	}
+
+	if v.Error != "foo" {
+		t.Errorf("unexpected error message: %q", v.Error)
+	}
+
+	expected := frame{Function: "github.com/danil/log0_test.TestErrorStack", File: testFile, Line: errorLine}
+
+	if len(v.Stack) == 0 || v.Stack[0] != expected {
+		t.Errorf("unexpected stack, expected first frame: %+v, received: %+v", expected, v.Stack)
+	}
+}
+
+func TestErrorStackText(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	kv := log0.StringErrorStackText("error", errors.New("foo"))
+	errorLine := line() - 1
+
+	p, err := kv.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %s", err)
+	}
+
+	var v struct {
+		Error string `json:"error"`
+		Stack string `json:"stack"`
+	}
+
+	err = json.Unmarshal(p, &v)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %s, json: %s", err, p)
+	}
+
+	expected := "github.com/danil/log0_test.TestErrorStackText\n\t" + testFile + ":" + strconv.Itoa(errorLine) + "\n"
+
+	if v.Error != "foo" || !strings.HasPrefix(v.Stack, expected) {
+		t.Errorf("unexpected error stack, expected prefix: %q, received: %q", expected, v.Stack)
+	}
+}
+
+func TestErrorStackNil(t *testing.T) {
+	p, err := log0.ErrorStack(nil).MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %s", err)
+	}
+
+	if string(p) != "null" {
+		t.Errorf("unexpected nil error stack: %s", p)
+	}
+}
+
+func TestLogStack(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output: &buf,
+		Keys:   [4]encoding.TextMarshaler{log0.String("message")},
+		Stack:  log0.String("stack"),
+	}
+
+	err := l.Warning("foo")
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	if strings.Contains(buf.String(), "stack") {
+		t.Errorf("unexpected stack below the stack level: %s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = l.Critical("bar")
+	criticalLine := line() - 1
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	var v struct {
+		Message string  `json:"message"`
+		Stack   []frame `json:"stack"`
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &v)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %s, json: %s", err, buf.Bytes())
+	}
+
+	expected := frame{Function: "github.com/danil/log0_test.TestLogStack", File: testFile, Line: criticalLine}
+
+	if v.Message != "bar" || len(v.Stack) == 0 || v.Stack[0] != expected {
+		t.Errorf("unexpected stack, expected first frame: %+v, received: %s", expected, buf.Bytes())
+	}
+}
+
+func TestLogStackText(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log0.Log{
+		Output:      &buf,
+		Keys:        [4]encoding.TextMarshaler{log0.String("message")},
+		Stack:       log0.String("stack"),
+		StackLevel:  log0.LevelWarning,
+		StackFormat: log0.StackText,
+	}
+
+	l1 := l.Get(log0.StringSeverity("severity", "4"))
+	defer l1.Put()
+
+	_, err := l1.Write([]byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	var v struct {
+		Stack string `json:"stack"`
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &v)
+	if err != nil {
+		t.Fatalf("unexpected unmarshal error: %s, json: %s", err, buf.Bytes())
+	}
+
+	expected := "github.com/danil/log0_test.TestLogStackText\n\t"
+
+	if !strings.HasPrefix(v.Stack, expected) {
+		t.Errorf("unexpected stack, expected prefix: %q, received: %q", expected, v.Stack)
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package syslog0 implements syslog writer of the RFC 5424
+// <https://www.rfc-editor.org/rfc/rfc5424>
+// and of the legacy RFC 3164 <https://www.rfc-editor.org/rfc/rfc3164>
+// formats for the log0 entries.
+package syslog0
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danil/log0"
+)
+
+// Formats of the syslog messages.
+const (
+	RFC5424 = iota // RFC5424 is a syslog protocol format.
+	RFC3164        // RFC3164 is a legacy BSD syslog format.
+)
+
+// Facility is a syslog facility code plus one,
+// so the zero value is an undefined facility.
+type Facility uint8
+
+// Syslog facilities.
+const (
+	Kern     Facility = iota + 1 // Kern is a kernel messages facility.
+	User                         // User is a user-level messages facility.
+	Mail                         // Mail is a mail system facility.
+	Daemon                       // Daemon is a system daemons facility.
+	Auth                         // Auth is a security/authorization messages facility.
+	Syslog                       // Syslog is a messages generated internally by syslogd facility.
+	LPR                          // LPR is a line printer subsystem facility.
+	News                         // News is a network news subsystem facility.
+	UUCP                         // UUCP is a UUCP subsystem facility.
+	Cron                         // Cron is a clock daemon facility.
+	AuthPriv                     // AuthPriv is a security/authorization private messages facility.
+	FTP                          // FTP is a FTP daemon facility.
+	NTP                          // NTP is a NTP subsystem facility.
+	Audit                        // Audit is a log audit facility.
+	Alert                        // Alert is a log alert facility.
+	Clock                        // Clock is a clock daemon facility.
+	Local0                       // Local0 is a local use 0 facility.
+	Local1                       // Local1 is a local use 1 facility.
+	Local2                       // Local2 is a local use 2 facility.
+	Local3                       // Local3 is a local use 3 facility.
+	Local4                       // Local4 is a local use 4 facility.
+	Local5                       // Local5 is a local use 5 facility.
+	Local6                       // Local6 is a local use 6 facility.
+	Local7                       // Local7 is a local use 7 facility.
+)
+
+// ErrNetwork is returned if the network is unknown.
+var ErrNetwork = errors.New("syslog0: unknown network")
+
+// local is a paths of the local syslog sockets.
+var local = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Writer writes entries to the syslog with the RFC 5424 or RFC 3164 header,
+// over the tcp and tls connections entries are framed
+// by the octet counting <https://www.rfc-editor.org/rfc/rfc6587#section-3.4.1>,
+// over the local unix stream connection entry is a single write
+// terminated by the newline as the local syslog daemons expect
+// <https://www.rfc-editor.org/rfc/rfc6587#section-3.4.2>,
+// over the datagram connections (unixgram and udp) entry is a datagram.
+// Failed write is retried once over the new connection.
+type Writer struct {
+	Network     string        // Network is a "unix", "unixgram", "udp", "tcp" or "tls" network, local syslog socket if empty.
+	Addr        string        // Addr is an address of the syslog, ignored if the network is empty.
+	TLS         *tls.Config   // TLS is a configuration of the "tls" network.
+	DialTimeout time.Duration // DialTimeout is a timeout of the connection, 10 seconds if zero.
+	Format      uint8         // Format is a format of the header: all except known = RFC 5424; 1 = RFC 3164.
+	Facility    Facility      // Facility is a facility of the entries, user if zero.
+	Level       log0.Level    // Level is a severity level of the entries of the Write, info if zero.
+	Hostname    string        // Hostname is a HOSTNAME of the header, os.Hostname if empty; RFC 3164 header of the local socket has no hostname.
+	AppName     string        // AppName is an APP-NAME of the header or a TAG of the RFC 3164 header, base name of the executable if empty.
+	ProcID      string        // ProcID is a PROCID of the header, process ID if empty.
+	MsgID       string        // MsgID is a MSGID of the RFC 5424 header, nil value if empty.
+	SDID        string        // SDID is a SD-ID of the STRUCTURED-DATA element of the top-level fields of the entry, for example "log0@32473", no structured data if empty.
+	MessageKeys []string      // MessageKeys are keys of the field of the entry which is a MSG instead of the structured data, "message" and "short_message" if nil.
+	Clock       log0.Clock    // Clock is a source of the TIMESTAMP, system clock is used if nil.
+
+	once     sync.Once
+	severity func(string) io.Writer
+	hostname string
+	appName  string
+	procID   string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	stream bool   // stream reports whether the connection is a stream.
+	local  bool   // local reports whether the connection is a local socket.
+	msg    []byte // msg is a syslog message.
+	frame  []byte // frame is an octet counting frame of the message.
+	param  []byte // param is a SD-PARAM of the message field.
+}
+
+func (w *Writer) init() {
+	w.severity = log0.SeverityWriters(w.write)
+
+	w.hostname = w.Hostname
+	if w.hostname == "" {
+		w.hostname, _ = os.Hostname()
+	}
+
+	w.appName = w.AppName
+	if w.appName == "" && len(os.Args) != 0 {
+		w.appName = filepath.Base(os.Args[0])
+	}
+
+	w.procID = w.ProcID
+	if w.procID == "" {
+		w.procID = strconv.Itoa(os.Getpid())
+	}
+}
+
+// Severity returns a writer of the entries with the syslog severity
+// of the level, see log0.SeverityWriters.
+func (w *Writer) Severity(severity string) io.Writer {
+	w.once.Do(w.init)
+	return w.severity(severity)
+}
+
+// Write writes the entry with the severity level of the Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	level := w.Level
+	if !level.Valid() {
+		level = log0.LevelInfo
+	}
+	return w.write(level, p)
+}
+
+func (w *Writer) write(level log0.Level, p []byte) (int, error) {
+	w.once.Do(w.init)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		err := w.dial()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	w.msg = w.append(w.msg[:0], level, bytes.TrimRight(p, "\n"))
+
+	out := w.msg
+	if w.stream && w.local {
+		w.msg = append(w.msg, '\n')
+		out = w.msg
+	} else if w.stream {
+		w.frame = strconv.AppendInt(w.frame[:0], int64(len(w.msg)), 10)
+		w.frame = append(append(w.frame, ' '), w.msg...)
+		out = w.frame
+	}
+
+	_, err := w.conn.Write(out)
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+
+		err = w.dial()
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = w.conn.Write(out)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *Writer) dial() error {
+	timeout := w.DialTimeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	d := &net.Dialer{Timeout: timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	switch w.Network {
+	case "":
+		for _, addr := range local {
+			for _, network := range [...]string{"unixgram", "unix"} {
+				conn, err = d.Dial(network, addr)
+				if err == nil {
+					w.conn, w.stream, w.local = conn, network == "unix", true
+					return nil
+				}
+			}
+		}
+		return err
+
+	case "tls":
+		conn, err = tls.DialWithDialer(d, "tcp", w.Addr, w.TLS)
+
+	case "unix", "unixgram", "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		conn, err = d.Dial(w.Network, w.Addr)
+
+	default:
+		return ErrNetwork
+	}
+	if err != nil {
+		return err
+	}
+
+	switch w.Network {
+	case "unixgram", "udp", "udp4", "udp6":
+		w.stream = false
+	default:
+		w.stream = true
+	}
+
+	w.conn = conn
+	w.local = w.Network == "unix" || w.Network == "unixgram"
+
+	return nil
+}
+
+// append appends syslog message of the entry to the dst.
+func (w *Writer) append(dst []byte, level log0.Level, p []byte) []byte {
+	facility := w.Facility
+	if facility < Kern || facility > Local7 {
+		facility = User
+	}
+
+	var t time.Time
+	if w.Clock != nil {
+		t = w.Clock.Now()
+	} else {
+		t = time.Now()
+	}
+
+	dst = append(dst, '<')
+	dst = strconv.AppendInt(dst, int64(facility-Kern)*8+int64(level.Syslog()), 10)
+	dst = append(dst, '>')
+
+	if w.Format == RFC3164 {
+		dst = t.AppendFormat(dst, time.Stamp)
+		dst = append(dst, ' ')
+		if !w.local {
+			dst = appendField(dst, w.hostname, 255)
+			dst = append(dst, ' ')
+		}
+		dst = appendField(dst, w.appName, 48)
+		dst = append(dst, '[')
+		dst = appendField(dst, w.procID, 128)
+		dst = append(dst, "]: "...)
+		return append(dst, p...)
+	}
+
+	dst = append(dst, "1 "...)
+	dst = t.AppendFormat(dst, "2006-01-02T15:04:05.000000Z07:00")
+	dst = append(dst, ' ')
+	dst = appendField(dst, w.hostname, 255)
+	dst = append(dst, ' ')
+	dst = appendField(dst, w.appName, 48)
+	dst = append(dst, ' ')
+	dst = appendField(dst, w.procID, 128)
+	dst = append(dst, ' ')
+	dst = appendField(dst, w.MsgID, 32)
+	dst = append(dst, ' ')
+	if w.SDID != "" {
+		keys := w.MessageKeys
+		if keys == nil {
+			keys = messageKeys
+		}
+		var ok bool
+		dst, w.param, ok = appendSD(dst, w.SDID, keys, w.param[:0], p)
+		if ok {
+			p = w.param
+		}
+	} else {
+		dst = append(dst, '-')
+	}
+	if len(p) != 0 {
+		dst = append(append(dst, ' '), p...)
+	}
+
+	return dst
+}
+
+// appendField appends header field limited by the maximum length
+// with characters other than printable US-ASCII replaced by underscore
+// or the nil value if the field is empty.
+func appendField(dst []byte, s string, max int) []byte {
+	if s == "" {
+		return append(dst, '-')
+	}
+	if len(s) > max {
+		s = s[:max]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+var messageKeys = []string{"message", "short_message"}
+
+// appendSD appends STRUCTURED-DATA element of the top-level fields
+// of the JSON object of the entry except the message field to the dst
+// and appends value of the first message field to the msg,
+// strings are unquoted and other values are raw JSON.
+// Nil value is appended and false is returned if the entry is not a JSON object.
+func appendSD(dst []byte, id string, keys []string, msg []byte, p []byte) ([]byte, []byte, bool) {
+	begin := len(dst)
+
+	dst = append(dst, '[')
+	dst = appendName(dst, id)
+
+	dec := json.NewDecoder(bytes.NewReader(p))
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('{') {
+		return append(dst[:begin], '-'), msg, false
+	}
+
+	var message bool
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return append(dst[:begin], '-'), msg, false
+		}
+
+		var raw json.RawMessage
+
+		err = dec.Decode(&raw)
+		if err != nil {
+			return append(dst[:begin], '-'), msg, false
+		}
+
+		name, _ := tok.(string)
+		if name == "" {
+			continue
+		}
+
+		var s string
+		if len(raw) != 0 && raw[0] == '"' && json.Unmarshal(raw, &s) == nil {
+			raw = json.RawMessage(s)
+		}
+
+		if !message && contains(keys, name) {
+			msg = append(msg, raw...)
+			message = true
+			continue
+		}
+
+		dst = append(dst, ' ')
+		dst = appendName(dst, name)
+		dst = append(dst, `="`...)
+		dst = appendValue(dst, raw)
+		dst = append(dst, '"')
+	}
+
+	return append(dst, ']'), msg, true
+}
+
+func contains(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// appendName appends SD-NAME of the at most 32 characters
+// with characters other than printable US-ASCII or which are '=', ']' or '"'
+// replaced by underscore.
+func appendName(dst []byte, s string) []byte {
+	if len(s) > 32 {
+		s = s[:32]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// appendValue appends PARAM-VALUE with '"', '\' and ']' escaped by backslash.
+func appendValue(dst []byte, s []byte) []byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\', ']':
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, s[i])
+	}
+	return dst
+}
+
+// Close closes the connection.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return err
+}
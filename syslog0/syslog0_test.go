@@ -0,0 +1,525 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package syslog0_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danil/log0"
+	"github.com/danil/log0/syslog0"
+)
+
+type clock struct{ t time.Time }
+
+func (c clock) Now() time.Time { return c.t }
+
+var now = clock{t: time.Date(2020, time.October, 15, 18, 9, 0, 123456789, time.UTC)}
+
+func line() int { _, _, l, _ := runtime.Caller(1); return l }
+
+func listenUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive returns next datagram.
+func receive(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	p := make([]byte, 65536)
+	err := conn.SetReadDeadline(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected deadline error: %s", err)
+	}
+	n, _, err := conn.ReadFrom(p)
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+	return string(p[:n])
+}
+
+// accept returns channel of the octet counting framed messages of the first connection.
+func accept(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+
+	c := make(chan string, 100)
+
+	go func() {
+		defer close(c)
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			s, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err != nil {
+				return
+			}
+			p := make([]byte, n)
+			_, err = io.ReadFull(r, p)
+			if err != nil {
+				return
+			}
+			c <- string(p)
+		}
+	}()
+
+	return c
+}
+
+func next(t *testing.T, c <-chan string) string {
+	t.Helper()
+	select {
+	case s := <-c:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("message is not received")
+	}
+	return ""
+}
+
+var WriterTestCases = []struct {
+	name     string
+	line     int
+	writer   *syslog0.Writer
+	input    string
+	expected string
+}{
+	{
+		name:     "RFC 5424",
+		line:     line(),
+		writer:   &syslog0.Writer{},
+		input:    `{"message":"Hello, syslog!"}` + "\n",
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - - {"message":"Hello, syslog!"}`,
+	},
+	{
+		name:     "RFC 5424 facility, level and msgid",
+		line:     line(),
+		writer:   &syslog0.Writer{Facility: syslog0.Local7, Level: log0.LevelError, MsgID: "ID47"},
+		input:    `{"message":"Hello, syslog!"}`,
+		expected: `<187>1 2020-10-15T18:09:00.123456Z example.tld app 42 ID47 - {"message":"Hello, syslog!"}`,
+	},
+	{
+		name:     "RFC 5424 header fields are sanitized",
+		line:     line(),
+		writer:   &syslog0.Writer{AppName: "my app", MsgID: "0123456789012345678901234567890123456789"},
+		input:    `{"message":"Hello, syslog!"}`,
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld my_app 42 01234567890123456789012345678901 - {"message":"Hello, syslog!"}`,
+	},
+	{
+		name:     "RFC 5424 structured data",
+		line:     line(),
+		writer:   &syslog0.Writer{SDID: "log0@32473"},
+		input:    `{"message":"Hello, \"syslog\"!","count":3,"ok":true,"obj":{"a":1},"a b=c":"d"}`,
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - [log0@32473 count="3" ok="true" obj="{\"a\":1}" a_b_c="d"] Hello, "syslog"!`,
+	},
+	{
+		name:     "RFC 5424 structured data escaping",
+		line:     line(),
+		writer:   &syslog0.Writer{SDID: "log0@32473"},
+		input:    `{"path":"C:\\tmp\\","bracket":"[a]]","quote":"say \"hi\"","mix":"\\\"]"}`,
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - [log0@32473 path="C:\\tmp\\" bracket="[a\]\]" quote="say \"hi\"" mix="\\\"\]"]`,
+	},
+	{
+		name:     "RFC 5424 structured data of the short message",
+		line:     line(),
+		writer:   &syslog0.Writer{SDID: "log0@32473"},
+		input:    `{"short_message":"Hello, syslog!","host":"example.tld"}`,
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - [log0@32473 host="example.tld"] Hello, syslog!`,
+	},
+	{
+		name:     "RFC 5424 structured data of the message keys",
+		line:     line(),
+		writer:   &syslog0.Writer{SDID: "log0@32473", MessageKeys: []string{"msg"}},
+		input:    `{"message":"foo","msg":"Hello, syslog!"}`,
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - [log0@32473 message="foo"] Hello, syslog!`,
+	},
+	{
+		name:     "RFC 5424 structured data of the plain text",
+		line:     line(),
+		writer:   &syslog0.Writer{SDID: "log0@32473"},
+		input:    "Hello, syslog!",
+		expected: `<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - - Hello, syslog!`,
+	},
+	{
+		name:     "RFC 3164",
+		line:     line(),
+		writer:   &syslog0.Writer{Format: syslog0.RFC3164, Facility: syslog0.Daemon, Level: log0.LevelWarning},
+		input:    `{"message":"Hello, syslog!"}` + "\n",
+		expected: `<28>Oct 15 18:09:00 example.tld app[42]: {"message":"Hello, syslog!"}`,
+	},
+}
+
+func TestWriter(t *testing.T) {
+	_, testFile, _, _ := runtime.Caller(0)
+	for _, tc := range WriterTestCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%s %d", tc.name, tc.line), func(t *testing.T) {
+			t.Parallel()
+			linkToExample := fmt.Sprintf("%s:%d", testFile, tc.line)
+
+			conn := listenUDP(t)
+
+			w := tc.writer
+			w.Network = "udp"
+			w.Addr = conn.LocalAddr().String()
+			w.Hostname = "example.tld"
+			if w.AppName == "" {
+				w.AppName = "app"
+			}
+			w.ProcID = "42"
+			w.Clock = now
+			defer w.Close()
+
+			n, err := w.Write([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("unexpected write error: %s %s", err, linkToExample)
+			}
+			if n != len(tc.input) {
+				t.Errorf("unexpected write length, expected: %d, recieved: %d %s", len(tc.input), n, linkToExample)
+			}
+
+			if s := receive(t, conn); s != tc.expected {
+				t.Errorf("unexpected message, expected: %q, recieved: %q %s", tc.expected, s, linkToExample)
+			}
+		})
+	}
+}
+
+func TestWriterSeverity(t *testing.T) {
+	conn := listenUDP(t)
+
+	w := &syslog0.Writer{
+		Network:  "udp",
+		Addr:     conn.LocalAddr().String(),
+		Hostname: "example.tld",
+		AppName:  "app",
+		ProcID:   "42",
+		Clock:    now,
+	}
+	defer w.Close()
+
+	l := &log0.Log{
+		Output:   w,
+		Severity: w.Severity,
+		Keys:     [4]encoding.TextMarshaler{log0.String("message")},
+	}
+
+	err := l.Warning("Hello, syslog!")
+	if err != nil {
+		t.Fatalf("unexpected log error: %s", err)
+	}
+
+	expected := `<12>1 2020-10-15T18:09:00.123456Z example.tld app 42 - - {"message":"Hello, syslog!","severity":"4"}`
+	if s := receive(t, conn); s != expected {
+		t.Errorf("unexpected message, expected: %q, recieved: %q", expected, s)
+	}
+
+	l1 := l.Get(log0.StringSeverity("severity", "crit"))
+	defer l1.Put()
+
+	_, err = l1.Write([]byte("Hello, critical!"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	expected = `<10>1 2020-10-15T18:09:00.123456Z example.tld app 42 - - {"message":"Hello, critical!","severity":"crit"}`
+	if s := receive(t, conn); s != expected {
+		t.Errorf("unexpected message, expected: %q, recieved: %q", expected, s)
+	}
+
+	if w.Severity("foo") != nil {
+		t.Error("unexpected writer of the unknown severity")
+	}
+}
+
+func TestWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer ln.Close()
+
+	c := accept(t, ln)
+
+	w := &syslog0.Writer{
+		Network:  "tcp",
+		Addr:     ln.Addr().String(),
+		Hostname: "example.tld",
+		AppName:  "app",
+		ProcID:   "42",
+		Clock:    now,
+	}
+	defer w.Close()
+
+	for _, s := range []string{"foo\n", "bar\nbaz\n"} {
+		_, err = w.Write([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	for _, expected := range []string{
+		"<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - - foo",
+		"<14>1 2020-10-15T18:09:00.123456Z example.tld app 42 - - bar\nbaz",
+	} {
+		if s := next(t, c); s != expected {
+			t.Errorf("unexpected message, expected: %q, recieved: %q", expected, s)
+		}
+	}
+}
+
+func TestWriterTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected key error: %s", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected certificate error: %s", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unexpected certificate error: %s", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer ln.Close()
+
+	c := accept(t, ln)
+
+	w := &syslog0.Writer{
+		Network:  "tls",
+		Addr:     ln.Addr().String(),
+		TLS:      &tls.Config{RootCAs: pool},
+		Format:   syslog0.RFC3164,
+		Hostname: "example.tld",
+		AppName:  "app",
+		ProcID:   "42",
+		Clock:    now,
+	}
+	defer w.Close()
+
+	_, err = w.Write([]byte("Hello, TLS!"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	expected := "<14>Oct 15 18:09:00 example.tld app[42]: Hello, TLS!"
+	if s := next(t, c); s != expected {
+		t.Errorf("unexpected message, expected: %q, recieved: %q", expected, s)
+	}
+}
+
+func TestWriterUnixgram(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unixgram is not supported")
+	}
+
+	dir, err := os.MkdirTemp("", "syslog0")
+	if err != nil {
+		t.Fatalf("unexpected temp dir error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	conn, err := net.ListenPacket("unixgram", filepath.Join(dir, "log"))
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer conn.Close()
+
+	w := &syslog0.Writer{
+		Network:  "unixgram",
+		Addr:     filepath.Join(dir, "log"),
+		Format:   syslog0.RFC3164,
+		Hostname: "example.tld",
+		AppName:  "app",
+		ProcID:   "42",
+		Clock:    now,
+	}
+	defer w.Close()
+
+	_, err = w.Write([]byte("Hello, unixgram!\n"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	// RFC 3164 header of the local socket has no hostname.
+	expected := "<14>Oct 15 18:09:00 app[42]: Hello, unixgram!"
+	if s := receive(t, conn); s != expected {
+		t.Errorf("unexpected message, expected: %q, recieved: %q", expected, s)
+	}
+}
+
+func TestWriterUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix is not supported")
+	}
+
+	dir, err := os.MkdirTemp("", "syslog0")
+	if err != nil {
+		t.Fatalf("unexpected temp dir error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ln, err := net.Listen("unix", filepath.Join(dir, "log"))
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer ln.Close()
+
+	c := make(chan string, 100)
+
+	go func() {
+		defer close(c)
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			s, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			c <- s
+		}
+	}()
+
+	w := &syslog0.Writer{
+		Network: "unix",
+		Addr:    filepath.Join(dir, "log"),
+		Format:  syslog0.RFC3164,
+		AppName: "app",
+		ProcID:  "42",
+		Clock:   now,
+	}
+	defer w.Close()
+
+	for _, s := range []string{"foo\n", "bar"} {
+		_, err = w.Write([]byte(s))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+	}
+
+	// Local unix stream messages are terminated by the newline instead of the octet counting.
+	for _, expected := range []string{
+		"<14>Oct 15 18:09:00 app[42]: foo\n",
+		"<14>Oct 15 18:09:00 app[42]: bar\n",
+	} {
+		if s := next(t, c); s != expected {
+			t.Errorf("unexpected message, expected: %q, recieved: %q", expected, s)
+		}
+	}
+}
+
+func TestWriterReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %s", err)
+	}
+	defer ln.Close()
+
+	first := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			first <- conn
+		}
+	}()
+
+	w := &syslog0.Writer{Network: "tcp", Addr: ln.Addr().String(), Hostname: "h", AppName: "a", ProcID: "1", Clock: now}
+	defer w.Close()
+
+	_, err = w.Write([]byte("foo"))
+	if err != nil {
+		t.Fatalf("unexpected write error: %s", err)
+	}
+
+	select {
+	case conn := <-first:
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("connection is not accepted")
+	}
+
+	c := accept(t, ln)
+
+	// Writes to the closed connection may succeed until the connection is reset.
+	for i := 0; i < 10; i++ {
+		_, err = w.Write([]byte("bar " + strconv.Itoa(i)))
+		if err != nil {
+			t.Fatalf("unexpected write error: %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s := next(t, c)
+	if !strings.HasPrefix(s, "<14>1 2020-10-15T18:09:00.123456Z h a 1 - - bar ") {
+		t.Errorf("unexpected message after reconnection: %q", s)
+	}
+}
+
+func TestWriterUnknownNetwork(t *testing.T) {
+	w := &syslog0.Writer{Network: "foo"}
+
+	_, err := w.Write([]byte("foo"))
+	if err != syslog0.ErrNetwork {
+		t.Errorf("unexpected error, expected: %s, recieved: %v", syslog0.ErrNetwork, err)
+	}
+}